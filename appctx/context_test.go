@@ -112,3 +112,24 @@ func TestFindGitRoot_NonGitDirectoryLogsDebugFallback(t *testing.T) {
 	})
 	require.Empty(t, warns, "non-git directories should not emit warn logs for fallback")
 }
+
+func TestFindGitRoot_UsesRuntimeCommander(t *testing.T) {
+	t.Parallel()
+
+	f := NewSandbox(t,
+		testutils.WithFixture("basic", "repo"),
+		testutils.WithWd("repo/basic"),
+	)
+
+	hostRoot := filepath.Join(f.GetJail(), "home", "testuser", "repo")
+	f.Commands().
+		On("git", "-C", "*", "rev-parse", "--show-toplevel").
+		Stdout(hostRoot + "\n")
+
+	root := proj.FindGitRoot(f.Context(), f.Runtime(), "/home/testuser/repo/basic")
+	assert.Equal(t, filepath.FromSlash("/home/testuser/repo"), root)
+
+	calls := f.Commands().Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, filepath.Join(f.GetJail(), "home", "testuser", "repo", "basic"), calls[0].Argv[2])
+}
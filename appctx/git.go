@@ -31,10 +31,15 @@ func FindGitRoot(ctx context.Context, rt *toolkit.Runtime, start string) string
 	}
 
 	// First, try using git itself to find the top-level directory. Using `-C`
-	// makes git operate relative to the provided path.
-	args := []string{"-C", start, "rev-parse", "--show-toplevel"}
-	if out, err := exec.CommandContext(ctx, "git", args...).Output(); err == nil {
-		if p := strings.TrimSpace(string(out)); p != "" {
+	// makes git operate relative to the provided path, which must be given as a
+	// host path because git does not see the runtime jail.
+	hostStart, err := rt.HostPath(start)
+	if err != nil {
+		hostStart = start
+	}
+	args := []string{"-C", hostStart, "rev-parse", "--show-toplevel"}
+	if out, err := rt.Command(ctx, "git", args...).Output(); err == nil {
+		if p, ok := fromHostPath(rt, strings.TrimSpace(string(out))); ok {
 			lg.Log(
 				context.Background(),
 				slog.LevelDebug,
@@ -43,7 +48,12 @@ func FindGitRoot(ctx context.Context, rt *toolkit.Runtime, start string) string
 			)
 			return p
 		}
-		lg.Log(context.Background(), slog.LevelDebug, "git rev-parse returned empty output")
+		lg.Log(
+			context.Background(),
+			slog.LevelDebug,
+			"git rev-parse returned no usable root",
+			slog.String("output", strings.TrimSpace(string(out))),
+		)
 	} else {
 		level := slog.LevelWarn
 		if isExpectedGitFallbackErr(err) {
@@ -80,6 +90,22 @@ func FindGitRoot(ctx context.Context, rt *toolkit.Runtime, start string) string
 	return ""
 }
 
+// fromHostPath maps a host path reported by an external program back to a
+// runtime path. It reports false for empty paths and paths outside the jail.
+func fromHostPath(rt *toolkit.Runtime, p string) (string, bool) {
+	if p == "" {
+		return "", false
+	}
+	j := rt.GetJail()
+	if j == "" {
+		return p, true
+	}
+	if !toolkit.IsInJail(j, p) {
+		return "", false
+	}
+	return toolkit.RemoveJailPrefix(j, p), true
+}
+
 func isExpectedGitFallbackErr(err error) bool {
 	if err == nil {
 		return false
@@ -95,7 +121,12 @@ func isExpectedGitFallbackErr(err error) bool {
 		return true
 	}
 
-	var exitErr *exec.ExitError
+	// git is not installed, or no scripted command matched in tests.
+	if errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+
+	var exitErr *toolkit.ExitError
 	if errors.As(err, &exitErr) {
 		stderr := strings.ToLower(strings.TrimSpace(string(exitErr.Stderr)))
 		if strings.Contains(stderr, "not a git repository") {
@@ -106,7 +137,7 @@ func isExpectedGitFallbackErr(err error) bool {
 		}
 		// Exit code 128 from git typically means the directory is not
 		// a git repository or does not exist.
-		if exitErr.ExitCode() == 128 {
+		if exitErr.Code == 128 {
 			return true
 		}
 	}
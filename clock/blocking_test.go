@@ -0,0 +1,114 @@
+package clock_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestClock_PendingCount(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	assert.Equal(t, 0, tc.PendingCount())
+
+	timer := tc.AfterFunc(time.Second, func() {})
+	tc.After(2 * time.Second)
+	ticker := tc.NewTicker(time.Minute)
+	assert.Equal(t, 3, tc.PendingCount())
+
+	timer.Stop()
+	assert.Equal(t, 2, tc.PendingCount())
+
+	tc.Advance(2 * time.Second)
+	assert.Equal(t, 1, tc.PendingCount(), "ticker stays pending after firing")
+
+	ticker.Stop()
+	assert.Equal(t, 0, tc.PendingCount())
+}
+
+func TestTestClock_BlockUntil(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	done := make(chan error, 1)
+	go func() {
+		done <- clock.Sleep(context.Background(), tc, time.Minute)
+	}()
+
+	tc.BlockUntil(1)
+	tc.Advance(time.Minute)
+	require.NoError(t, <-done)
+}
+
+func TestTestClock_BlockUntilContext(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, tc.BlockUntilContext(ctx, 1), context.DeadlineExceeded)
+}
+
+func TestTestClock_AdvanceToNext(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	assert.False(t, tc.AdvanceToNext())
+	assert.Equal(t, seed, tc.Now())
+
+	ch := tc.After(90 * time.Second)
+	tc.After(5 * time.Minute)
+
+	require.True(t, tc.AdvanceToNext())
+	assert.Equal(t, seed.Add(90*time.Second), tc.Now())
+	assert.Equal(t, seed.Add(90*time.Second), <-ch)
+
+	require.True(t, tc.AdvanceToNext())
+	assert.Equal(t, seed.Add(5*time.Minute), tc.Now())
+	assert.False(t, tc.AdvanceToNext())
+}
+
+func TestTestClock_RunUntilIdle(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ticker := tc.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// A callback chain: each run schedules the next until five have run.
+	var runs atomic.Int32
+	var step func()
+	step = func() {
+		if runs.Add(1) < 5 {
+			tc.AfterFunc(time.Minute, step)
+		}
+	}
+	tc.AfterFunc(time.Minute, step)
+
+	steps := tc.RunUntilIdle()
+	assert.Equal(t, 5, steps)
+	assert.Equal(t, int32(5), runs.Load())
+	assert.Equal(t, seed.Add(5*time.Minute), tc.Now())
+	assert.Equal(t, 1, tc.PendingCount(), "only the ticker remains")
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	assert.NoError(t, clock.Sleep(context.Background(), tc, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- clock.Sleep(ctx, tc, time.Hour) }()
+	tc.BlockUntil(1)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, 0, tc.PendingCount(), "cancelled sleep releases its timer")
+}
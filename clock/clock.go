@@ -1,6 +1,7 @@
 package clock
 
 import (
+	"context"
 	"time"
 )
 
@@ -30,18 +31,95 @@ type TestClock struct {
 	sched *scheduler
 }
 
+// TestClockOption configures [NewTestClock].
+type TestClockOption func(*TestClock)
+
+// WithSyncCallbacks runs AfterFunc callbacks inline, in deadline order,
+// before Advance returns, instead of in new goroutines. The clock reads each
+// callback's deadline while it runs, and timers a callback registers fire in
+// the same Advance when they fall due before its target time. Tests can then
+// assert on a callback's effects straight after Advance, without sleeping or
+// polling. Callbacks must not block waiting for another Advance.
+func WithSyncCallbacks() TestClockOption {
+	return func(c *TestClock) {
+		c.sched.syncCallbacks = true
+	}
+}
+
+// WithBlockingTicks makes Advance wait for a ticker's receiver to take the
+// pending tick before delivering the next one, so no tick is dropped.
+// Advance blocks until the tick is received or the ticker is stopped.
+func WithBlockingTicks() TestClockOption {
+	return func(c *TestClock) {
+		c.sched.blockingTicks = true
+	}
+}
+
+// DroppedTick describes a tick that was discarded because the ticker's
+// channel still held an unread tick.
+type DroppedTick struct {
+	// ID is the ticker's ID, as used in traces.
+	ID uint64
+	// At is the time of the dropped tick.
+	At time.Time
+}
+
+// WithDroppedTickHandler calls f for each dropped tick, from the goroutine
+// calling Advance. Use it to fail tests that expect every tick to be
+// handled:
+//
+//	clock.WithDroppedTickHandler(func(d clock.DroppedTick) {
+//		t.Errorf("tick at %s dropped", d.At)
+//	})
+func WithDroppedTickHandler(f func(DroppedTick)) TestClockOption {
+	return func(c *TestClock) {
+		c.sched.onDrop = f
+	}
+}
+
+// DroppedTicks returns the number of ticks dropped so far because a ticker's
+// channel was full.
+func (c *TestClock) DroppedTicks() int {
+	c.sched.mu.Lock()
+	defer c.sched.mu.Unlock()
+	return c.sched.dropped
+}
+
 // NewTestClock constructs a TestClock seeded to the provided time.
-func NewTestClock(initial time.Time) *TestClock {
+func NewTestClock(initial time.Time, opts ...TestClockOption) *TestClock {
 	c := &TestClock{now: initial}
 	c.sched = newScheduler(&c.now)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
 	return c
 }
 
-// Now returns the current time of the TestClock.
+// Now returns the current time of the TestClock, in the clock's location
+// when one is set with [TestClock.SetLocation].
 func (c *TestClock) Now() time.Time {
 	c.sched.mu.Lock()
 	defer c.sched.mu.Unlock()
-	return c.now
+	return c.sched.inLocked(c.now)
+}
+
+// SetLocation makes Now, and the times delivered by tickers and After
+// channels, report times in loc, so formatted output does not depend on the
+// host time zone. A nil loc reports times in the location of the time the
+// clock was created or Set with.
+func (c *TestClock) SetLocation(loc *time.Location) {
+	c.sched.mu.Lock()
+	c.sched.loc = loc
+	c.sched.mu.Unlock()
+}
+
+// Location returns the location set with [TestClock.SetLocation], or nil.
+func (c *TestClock) Location() *time.Location {
+	c.sched.mu.Lock()
+	defer c.sched.mu.Unlock()
+	return c.sched.loc
 }
 
 // Advance moves the TestClock forward by d, firing any tickers, timers, or
@@ -85,8 +163,8 @@ func (c *TestClock) After(d time.Duration) <-chan time.Time {
 	return e.afterC
 }
 
-// AfterFunc calls f in a new goroutine after duration d and returns a [Timer]
-// that can cancel the call.
+// AfterFunc calls f in a new goroutine after duration d, or inline with
+// [WithSyncCallbacks], and returns a [Timer] that can cancel the call.
 func (c *TestClock) AfterFunc(d time.Duration, f func()) Timer {
 	c.sched.mu.Lock()
 	e := c.sched.addAfterFunc(d, f)
@@ -103,6 +181,92 @@ func (c *TestClock) Since(t time.Time) time.Duration {
 	return c.Now().Sub(t)
 }
 
+// PendingCount returns the number of timers, After channels and tickers that
+// are waiting to fire. Stopped and already fired timers are not counted; a
+// running ticker counts once.
+func (c *TestClock) PendingCount() int {
+	c.sched.mu.Lock()
+	defer c.sched.mu.Unlock()
+	return c.sched.pendingLocked()
+}
+
+// BlockUntil blocks until at least n timers, After channels or tickers are
+// pending (see [TestClock.PendingCount]). Call it before Advance to be sure
+// the code under test has registered its timers:
+//
+//	go worker(ctx, clk)
+//	clk.BlockUntil(1)
+//	clk.Advance(time.Minute)
+func (c *TestClock) BlockUntil(n int) {
+	_ = c.BlockUntilContext(context.Background(), n)
+}
+
+// BlockUntilContext is like [TestClock.BlockUntil] but gives up when ctx
+// ends, returning ctx.Err().
+func (c *TestClock) BlockUntilContext(ctx context.Context, n int) error {
+	return c.sched.wait(ctx, func() bool { return c.sched.pendingLocked() >= n })
+}
+
+// AdvanceToNext advances the clock to the deadline of the earliest pending
+// timer or ticker and fires everything due at that instant. It reports
+// false, leaving the clock unchanged, when nothing is pending.
+func (c *TestClock) AdvanceToNext() bool {
+	return c.sched.advanceToNext(false)
+}
+
+// RunUntilIdle repeatedly advances to the next pending timer until none
+// remain, and returns the number of steps taken. Before each step it waits
+// for AfterFunc callbacks started by the previous step to return, so timers
+// they register are run too. Tickers fire along the way but never keep the
+// clock busy on their own.
+//
+// Timers registered by goroutines woken through After channels are only
+// seen if they are registered before the next step; use BlockUntil for
+// those. RunUntilIdle panics if the schedule does not settle after
+// [MaxIdleSteps] steps.
+func (c *TestClock) RunUntilIdle() int {
+	steps := 0
+	for {
+		_ = c.sched.wait(context.Background(), func() bool { return c.sched.inflight == 0 })
+		if steps >= MaxIdleSteps {
+			panic("clock: RunUntilIdle did not settle")
+		}
+		if !c.sched.advanceToNext(true) {
+			return steps
+		}
+		steps++
+	}
+}
+
+// MaxIdleSteps bounds the number of steps [TestClock.RunUntilIdle] takes
+// before assuming a timer keeps rescheduling itself forever.
+const MaxIdleSteps = 100000
+
+// Sleep pauses until d has passed on c or ctx ends, whichever is first. It
+// returns ctx.Err() when interrupted. With a [TestClock], Sleep returns once
+// the clock is advanced past the wake-up time.
+func Sleep(ctx context.Context, c SchedulingClock, d time.Duration) error {
+	if c == nil {
+		c = OsClock{}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+	woke := make(chan struct{})
+	timer := c.AfterFunc(d, func() { close(woke) })
+	select {
+	case <-woke:
+		return nil
+	case <-ctx.Done():
+		// Stop the timer so an abandoned sleep is not left pending.
+		timer.Stop()
+		return ctx.Err()
+	}
+}
+
 var _ Clock = (*OsClock)(nil)
 var _ Clock = (*TestClock)(nil)
 var _ SchedulingClock = (*TestClock)(nil)
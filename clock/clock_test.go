@@ -26,6 +26,22 @@ func TestTestClockBasics(t *testing.T) {
 	assert.Equal(t, newt, c.Now())
 }
 
+func TestTestClock_SetLocation(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC))
+	assert.Nil(t, tc.Location())
+
+	est := time.FixedZone("EST", -5*3600)
+	tc.SetLocation(est)
+	assert.Equal(t, est, tc.Location())
+	assert.Equal(t, "2020-01-01T07:00:00-05:00", tc.Now().Format(time.RFC3339))
+
+	ch := tc.After(time.Hour)
+	tc.Advance(time.Hour)
+	assert.Equal(t, "2020-01-01T08:00:00-05:00", (<-ch).Format(time.RFC3339))
+}
+
 func TestOrDefaultUsesProvidedClock(t *testing.T) {
 	initial := time.Date(2019, time.March, 3, 4, 5, 6, 0, time.UTC)
 	tc := clock.NewTestClock(initial)
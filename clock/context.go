@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithDeadline is like [context.WithDeadline] but measures the deadline with
+// c instead of wall time. With a [TestClock], the returned context expires
+// only when [TestClock.Advance] reaches d, so timeout paths can be tested
+// without sleeping.
+//
+// Once the deadline passes, Err returns [context.DeadlineExceeded], and so do
+// contexts derived from it with the standard library. Cancelling the parent
+// or calling the returned CancelFunc ends the context early, as with the
+// standard library, and [context.Cause] reports the parent's cause. Deadline
+// reports the earlier of d and the parent's deadline. A nil c uses [OsClock].
+func WithDeadline(parent context.Context, c SchedulingClock, d time.Time) (context.Context, context.CancelFunc) {
+	if c == nil {
+		c = OsClock{}
+	}
+	ctx := &deadlineContext{parent: parent, deadline: d, done: make(chan struct{})}
+	cancel := func() { ctx.cancel(context.Canceled) }
+
+	wait := d.Sub(c.Now())
+	if wait <= 0 {
+		ctx.cancel(context.DeadlineExceeded)
+		return ctx, cancel
+	}
+
+	// Hold the lock while arming so a parent that is already done cannot
+	// cancel ctx before the stop functions are recorded.
+	ctx.mu.Lock()
+	ctx.stopParent = context.AfterFunc(parent, func() { ctx.cancel(parent.Err()) })
+	ctx.timer = c.AfterFunc(wait, func() { ctx.cancel(context.DeadlineExceeded) })
+	ctx.mu.Unlock()
+	return ctx, cancel
+}
+
+// WithTimeout returns WithDeadline(parent, c, c.Now().Add(timeout)).
+func WithTimeout(parent context.Context, c SchedulingClock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if c == nil {
+		c = OsClock{}
+	}
+	return WithDeadline(parent, c, c.Now().Add(timeout))
+}
+
+// deadlineContext is a context that expires at a clock-measured deadline.
+// It has its own Done channel and error rather than wrapping a cancel
+// context, so contexts derived from it observe its Err, including
+// context.DeadlineExceeded.
+type deadlineContext struct {
+	parent   context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu         sync.Mutex
+	err        error
+	stopParent func() bool
+	timer      Timer
+}
+
+// Deadline returns the clock deadline, or the parent's deadline if that is
+// earlier. The clock deadline is measured by the clock given to
+// WithDeadline, which may differ from wall time.
+func (c *deadlineContext) Deadline() (time.Time, bool) {
+	if pd, ok := c.parent.Deadline(); ok && pd.Before(c.deadline) {
+		return pd, true
+	}
+	return c.deadline, true
+}
+
+func (c *deadlineContext) Done() <-chan struct{} { return c.done }
+
+func (c *deadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *deadlineContext) Value(key any) any { return c.parent.Value(key) }
+
+// cancel ends the context with err and releases the timer and the parent
+// watch. Only the first call has an effect.
+func (c *deadlineContext) cancel(err error) {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.err = err
+	close(c.done)
+	stop, timer := c.stopParent, c.timer
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+	if timer != nil {
+		timer.Stop()
+	}
+}
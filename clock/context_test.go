@@ -0,0 +1,125 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitDone(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("context was not cancelled")
+	}
+}
+
+func TestWithTimeout_ExpiresOnAdvance(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := clock.WithTimeout(context.Background(), tc, 5*time.Second)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, seed.Add(5*time.Second), deadline)
+
+	tc.Advance(4 * time.Second)
+	assert.NoError(t, ctx.Err())
+
+	tc.Advance(time.Second)
+	waitDone(t, ctx)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+}
+
+func TestWithDeadline_PastDeadline(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := clock.WithDeadline(context.Background(), tc, seed.Add(-time.Second))
+	defer cancel()
+
+	waitDone(t, ctx)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestWithTimeout_CancelBeforeDeadline(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := clock.WithTimeout(context.Background(), tc, time.Minute)
+	cancel()
+
+	waitDone(t, ctx)
+	assert.Equal(t, context.Canceled, ctx.Err())
+
+	tc.Advance(time.Hour)
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestWithTimeout_ParentCancel(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	parent, cancelParent := context.WithCancelCause(context.Background())
+	ctx, cancel := clock.WithTimeout(parent, tc, time.Minute)
+	defer cancel()
+
+	boom := errors.New("boom")
+	cancelParent(boom)
+	waitDone(t, ctx)
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, boom, context.Cause(ctx))
+}
+
+func TestWithTimeout_OsClock(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := clock.WithTimeout(context.Background(), clock.OsClock{}, 10*time.Millisecond)
+	defer cancel()
+	waitDone(t, ctx)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestWithTimeout_DerivedContextSeesDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := clock.WithTimeout(context.Background(), tc, 5*time.Second)
+	defer cancel()
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	valued := context.WithValue(ctx, struct{}{}, "v")
+
+	tc.Advance(5 * time.Second)
+	waitDone(t, child)
+	assert.Equal(t, context.DeadlineExceeded, child.Err())
+	assert.ErrorIs(t, context.Cause(child), context.DeadlineExceeded)
+	assert.Equal(t, context.DeadlineExceeded, valued.Err())
+}
+
+func TestWithTimeout_ParentDeadlineEarlier(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	parent, cancelParent := clock.WithTimeout(context.Background(), tc, time.Second)
+	defer cancelParent()
+	ctx, cancel := clock.WithTimeout(parent, tc, time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, seed.Add(time.Second), deadline)
+
+	tc.Advance(time.Second)
+	waitDone(t, ctx)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
@@ -0,0 +1,155 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer runs a function once a burst of triggers has gone quiet. Create
+// one with [Debounce].
+type Debouncer struct {
+	c SchedulingClock
+	d time.Duration
+	f func()
+
+	mu      sync.Mutex
+	timer   Timer
+	gen     uint64
+	stopped bool
+}
+
+// Debounce returns a Debouncer that calls f after d has passed on c without
+// another call to [Debouncer.Trigger]. Every trigger restarts the wait, so a
+// steady stream of triggers closer together than d postpones f until the
+// stream stops. f runs in its own goroutine. A nil c uses [OsClock].
+func Debounce(c SchedulingClock, d time.Duration, f func()) *Debouncer {
+	if c == nil {
+		c = OsClock{}
+	}
+	return &Debouncer{c: c, d: d, f: f}
+}
+
+// Trigger restarts the wait.
+func (b *Debouncer) Trigger() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.gen++
+	gen := b.gen
+	b.timer = b.c.AfterFunc(b.d, func() { b.fire(gen) })
+}
+
+func (b *Debouncer) fire(gen uint64) {
+	b.mu.Lock()
+	// A trigger that raced with this callback has already scheduled a
+	// newer one.
+	if b.stopped || gen != b.gen {
+		b.mu.Unlock()
+		return
+	}
+	b.timer = nil
+	b.mu.Unlock()
+	b.f()
+}
+
+// Flush runs f now if a call is pending and reports whether it did.
+func (b *Debouncer) Flush() bool {
+	b.mu.Lock()
+	if b.stopped || b.timer == nil || !b.timer.Stop() {
+		b.mu.Unlock()
+		return false
+	}
+	b.timer = nil
+	b.gen++
+	b.mu.Unlock()
+	b.f()
+	return true
+}
+
+// Stop cancels any pending call. Later triggers are ignored.
+func (b *Debouncer) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+// Throttler runs a function at most once per interval. Create one with
+// [Throttle].
+type Throttler struct {
+	c SchedulingClock
+	d time.Duration
+	f func()
+
+	mu      sync.Mutex
+	lead    Timer // runs the call that opened the current window
+	timer   Timer // ends the current window; nil when idle
+	pending bool
+	stopped bool
+}
+
+// Throttle returns a Throttler that calls f at most once every d on c. The
+// first [Throttler.Trigger] runs f at once and opens a window of length d;
+// triggers inside the window are coalesced into a single trailing call when
+// the window ends, which opens the next window. Every call, including the
+// first, is scheduled with c.AfterFunc, so f runs in its own goroutine, and
+// a [TestClock] runs it on its next Advance or inline with
+// [WithSyncCallbacks]. A nil c uses [OsClock].
+func Throttle(c SchedulingClock, d time.Duration, f func()) *Throttler {
+	if c == nil {
+		c = OsClock{}
+	}
+	return &Throttler{c: c, d: d, f: f}
+}
+
+// Trigger requests a call to f.
+func (t *Throttler) Trigger() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	if t.timer != nil {
+		t.pending = true
+		return
+	}
+	t.timer = t.c.AfterFunc(t.d, t.windowEnd)
+	t.lead = t.c.AfterFunc(0, t.f)
+}
+
+func (t *Throttler) windowEnd() {
+	t.mu.Lock()
+	if t.stopped || !t.pending {
+		t.timer = nil
+		t.mu.Unlock()
+		return
+	}
+	t.pending = false
+	t.timer = t.c.AfterFunc(t.d, t.windowEnd)
+	t.mu.Unlock()
+	t.f()
+}
+
+// Stop cancels any call that has not started. Later triggers are ignored.
+func (t *Throttler) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	t.pending = false
+	if t.lead != nil {
+		t.lead.Stop()
+		t.lead = nil
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
@@ -0,0 +1,68 @@
+package clock_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebounce(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	var calls atomic.Int32
+	d := clock.Debounce(tc, 100*time.Millisecond, func() { calls.Add(1) })
+
+	for range 5 {
+		d.Trigger()
+		tc.Advance(50 * time.Millisecond)
+	}
+	assert.Zero(t, calls.Load(), "triggers closer than the wait postpone the call")
+
+	tc.Advance(50 * time.Millisecond)
+	tc.RunUntilIdle()
+	assert.Equal(t, int32(1), calls.Load())
+
+	d.Trigger()
+	assert.True(t, d.Flush())
+	assert.Equal(t, int32(2), calls.Load())
+	assert.False(t, d.Flush(), "nothing pending")
+
+	d.Trigger()
+	d.Stop()
+	d.Trigger()
+	tc.Advance(time.Second)
+	tc.RunUntilIdle()
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Zero(t, tc.PendingCount())
+}
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithSyncCallbacks())
+	var calls atomic.Int32
+	th := clock.Throttle(tc, time.Second, func() { calls.Add(1) })
+	defer th.Stop()
+
+	th.Trigger()
+	tc.Advance(0)
+	assert.Equal(t, int32(1), calls.Load(), "first trigger runs at once")
+
+	for range 10 {
+		th.Trigger()
+		tc.Advance(50 * time.Millisecond)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "triggers inside the window wait")
+
+	tc.Advance(500 * time.Millisecond)
+	tc.RunUntilIdle()
+	assert.Equal(t, int32(2), calls.Load(), "one trailing call for the burst")
+
+	tc.RunUntilIdle()
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Zero(t, tc.PendingCount(), "idle after a quiet window")
+}
@@ -20,4 +20,35 @@
 // value already implements [SchedulingClock], it is returned as-is. Otherwise
 // it is wrapped in an adapter that provides OS-backed scheduling while
 // delegating Now() to the original clock.
+//
+// # Driving a TestClock
+//
+// Code under test usually registers timers from its own goroutine, so a test
+// that calls Advance too early races it. [TestClock.BlockUntil] waits until
+// the expected number of timers is pending; [TestClock.AdvanceToNext] and
+// [TestClock.RunUntilIdle] jump straight to pending deadlines. [Sleep],
+// [WithTimeout] and [WithDeadline] give production code clock-aware
+// replacements for time.Sleep and context.WithTimeout.
+//
+// When a scheduling test fails, [TestClock.LogTraceOnFailure] (or the
+// [WithTrace] option and [TestClock.Trace]) records every registration,
+// fire, stop and reset and prints them as a timeline.
+//
+// By default Advance runs AfterFunc callbacks in new goroutines and drops a
+// tick when the ticker channel is full, as the time package does.
+// [WithSyncCallbacks] runs callbacks inline before Advance returns,
+// [WithBlockingTicks] waits for each tick to be received, and
+// [WithDroppedTickHandler] reports dropped ticks.
+//
+// # Rate limiting
+//
+// [NewLimiter] is a token bucket, and [Debounce] and [Throttle] coalesce
+// bursts of events. All three schedule through a [SchedulingClock], so their
+// behavior under bursts can be checked by advancing a TestClock.
+//
+// # Timing
+//
+// [Stopwatch] records laps and nested spans for the phases of a command,
+// renders them with [Stopwatch.WriteSummary] for a --timings flag, and logs
+// span durations through slog.
 package clock
@@ -0,0 +1,258 @@
+// Package human parses and formats times and durations the way people type
+// and read them on the command line: "--since 2d", "--until yesterday",
+// "--older-than 3w", "updated 5 minutes ago".
+//
+// Relative expressions are resolved against a [clock.Clock], so a
+// [clock.TestClock] makes results reproducible. Calendar words such as
+// "yesterday" use the time zone of the clock's readings. Pass the
+// toolkit.Runtime itself, whose Now reports the runtime time zone, rather
+// than rt.Clock(), whose OsClock readings use the host zone:
+//
+//	since, err := human.ParseTime(rt, flagSince)
+//	fmt.Fprintf(w, "updated %s\n", human.FormatRelative(rt, item.Updated))
+package human
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+)
+
+// Units beyond those of [time.ParseDuration]. Months and years are
+// approximations and ignore calendar lengths.
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+var units = map[string]time.Duration{
+	"ns": time.Nanosecond, "nanosecond": time.Nanosecond, "nanoseconds": time.Nanosecond,
+	"us": time.Microsecond, "µs": time.Microsecond, "microsecond": time.Microsecond, "microseconds": time.Microsecond,
+	"ms": time.Millisecond, "millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": Day, "day": Day, "days": Day,
+	"w": Week, "wk": Week, "wks": Week, "week": Week, "weeks": Week,
+	"mo": Month, "month": Month, "months": Month,
+	"y": Year, "yr": Year, "yrs": Year, "year": Year, "years": Year,
+}
+
+// ParseDuration parses a non-negative duration such as "90m", "1h30m",
+// "3w", "1d12h" or "2 days 4 hours". It accepts the units of
+// [time.ParseDuration] plus days (d), weeks (w), months (mo, 30 days) and
+// years (y, 365 days), spelled-out unit names, and spaces, commas or "and"
+// between terms.
+func ParseDuration(s string) (time.Duration, error) {
+	in := strings.ToLower(strings.TrimSpace(s))
+	if in == "" {
+		return 0, fmt.Errorf("human: empty duration")
+	}
+
+	var total float64
+	rest := in
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if r, ok := strings.CutPrefix(rest, "and "); ok {
+			rest = strings.TrimLeft(r, " ")
+		}
+		if rest == "" {
+			break
+		}
+
+		n := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if n == 0 {
+			return 0, fmt.Errorf("human: invalid duration %q", s)
+		}
+		if n < 0 {
+			return 0, fmt.Errorf("human: missing unit in duration %q", s)
+		}
+		value, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return 0, fmt.Errorf("human: invalid duration %q", s)
+		}
+		rest = strings.TrimLeft(rest[n:], " ")
+
+		u := strings.IndexFunc(rest, func(r rune) bool { return r == ' ' || r == ',' || r == '.' || (r >= '0' && r <= '9') })
+		if u < 0 {
+			u = len(rest)
+		}
+		unit, ok := units[rest[:u]]
+		if !ok {
+			if u == 0 {
+				return 0, fmt.Errorf("human: missing unit in duration %q", s)
+			}
+			return 0, fmt.Errorf("human: unknown unit %q in duration %q", rest[:u], s)
+		}
+		rest = rest[u:]
+		total += value * float64(unit)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range.
+	if total >= math.MaxInt64 {
+		return 0, fmt.Errorf("human: duration %q out of range", s)
+	}
+	return time.Duration(total), nil
+}
+
+// ParseTime parses a time expression relative to c.Now(). Accepted forms:
+//
+//   - "now", "today", "yesterday", "tomorrow"; the last three mean the start
+//     of that day
+//   - a duration, meaning that long ago: "2d", "3 weeks", "2d ago"
+//   - a future duration: "in 3h", "+3h"; "-3h" is the same as "3h ago"
+//   - an absolute time: RFC 3339, "2006-01-02 15:04:05", "2006-01-02 15:04",
+//     "2006-01-02T15:04" or "2006-01-02"
+//
+// Days and absolute times without a zone are taken in the location of
+// c.Now(). A nil c uses the default clock.
+func ParseTime(c clock.Clock, s string) (time.Time, error) {
+	now := clock.OrDefault(c).Now()
+	in := strings.TrimSpace(s)
+	lower := strings.ToLower(in)
+
+	switch lower {
+	case "":
+		return time.Time{}, fmt.Errorf("human: empty time")
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now, 0), nil
+	case "yesterday":
+		return startOfDay(now, -1), nil
+	case "tomorrow":
+		return startOfDay(now, 1), nil
+	}
+
+	sign := -1
+	expr := lower
+	if r, ok := strings.CutSuffix(expr, " ago"); ok {
+		expr = r
+	} else if r, ok := strings.CutPrefix(expr, "in "); ok {
+		expr, sign = r, 1
+	} else if r, ok := strings.CutPrefix(expr, "+"); ok {
+		expr, sign = r, 1
+	} else if r, ok := strings.CutPrefix(expr, "-"); ok {
+		expr = r
+	}
+	if d, err := ParseDuration(expr); err == nil {
+		return now.Add(time.Duration(sign) * d), nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, in, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("human: cannot parse time %q", s)
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+}
+
+func startOfDay(t time.Time, days int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+days, 0, 0, 0, 0, t.Location())
+}
+
+// compactUnits are the units used by FormatDuration, largest first.
+var compactUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{Week, "w"}, {Day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"},
+}
+
+// FormatDuration formats d compactly using its two most significant units,
+// such as "2d4h", "1h30m" or "45s", dropping the remainder. Durations under
+// a second are rounded to the millisecond. The result is accepted by
+// [ParseDuration].
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(absDuration(d))
+	}
+	if d < time.Second {
+		if d >= time.Millisecond {
+			return d.Round(time.Millisecond).String()
+		}
+		return d.String()
+	}
+
+	var b strings.Builder
+	parts := 0
+	for _, u := range compactUnits {
+		if d < u.d {
+			if parts > 0 {
+				// Keep the two units adjacent: 1d0h5m reads as 1d.
+				parts++
+			}
+		} else {
+			fmt.Fprintf(&b, "%d%s", d/u.d, u.name)
+			d %= u.d
+			parts++
+		}
+		if parts == 2 {
+			break
+		}
+	}
+	return b.String()
+}
+
+// absDuration returns -d for a negative d, clamping math.MinInt64, which has
+// no positive counterpart, to math.MaxInt64. Formatting drops remainders, so
+// the one nanosecond lost does not show.
+func absDuration(d time.Duration) time.Duration {
+	if d == math.MinInt64 {
+		return math.MaxInt64
+	}
+	return -d
+}
+
+// relativeUnits are the units used by FormatRelative, largest first.
+var relativeUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{Year, "year"}, {Month, "month"}, {Week, "week"}, {Day, "day"},
+	{time.Hour, "hour"}, {time.Minute, "minute"}, {time.Second, "second"},
+}
+
+// FormatRelative describes t relative to c.Now() in the largest whole unit,
+// such as "5 minutes ago", "in 2 hours" or "3 weeks ago". Times within ten
+// seconds of now are "just now". A nil c uses the default clock.
+func FormatRelative(c clock.Clock, t time.Time) string {
+	d := clock.OrDefault(c).Now().Sub(t)
+	future := d < 0
+	if future {
+		d = absDuration(d)
+	}
+	if d < 10*time.Second {
+		return "just now"
+	}
+	for _, u := range relativeUnits {
+		if d < u.d {
+			continue
+		}
+		n := int64(d / u.d)
+		phrase := fmt.Sprintf("%d %s", n, u.name)
+		if n != 1 {
+			phrase += "s"
+		}
+		if future {
+			return "in " + phrase
+		}
+		return phrase + " ago"
+	}
+	return "just now"
+}
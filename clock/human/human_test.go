@@ -0,0 +1,156 @@
+package human_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/clock/human"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seed is a Wednesday afternoon.
+var seed = time.Date(2025, time.January, 15, 14, 30, 0, 0, time.UTC)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"90m", 90 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"2d", 48 * time.Hour},
+		{"3w", 21 * human.Day},
+		{"1d12h", 36 * time.Hour},
+		{"2 days", 2 * human.Day},
+		{"1 week, 2 days and 3 hours", human.Week + 2*human.Day + 3*time.Hour},
+		{"1mo", 30 * human.Day},
+		{"1y", 365 * human.Day},
+		{"250ms", 250 * time.Millisecond},
+		{" 10 Minutes ", 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := human.ParseDuration(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, in := range []string{"", "d", "10", "3 fortnights", "1h-", "-2d", "9223372036854775808ns"} {
+		_, err := human.ParseDuration(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", seed},
+		{"today", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"Yesterday", time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"2d", seed.Add(-48 * time.Hour)},
+		{"3w", seed.Add(-3 * human.Week)},
+		{"5 minutes ago", seed.Add(-5 * time.Minute)},
+		{"-1h", seed.Add(-time.Hour)},
+		{"in 3 hours", seed.Add(3 * time.Hour)},
+		{"+1d", seed.Add(human.Day)},
+		{"2024-12-25", time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{"2024-12-25 08:15", time.Date(2024, 12, 25, 8, 15, 0, 0, time.UTC)},
+		{"2024-12-25T08:15:30+02:00", time.Date(2024, 12, 25, 6, 15, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := human.ParseTime(tc, tt.in)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+
+	_, err := human.ParseTime(tc, "next blue moon")
+	assert.Error(t, err)
+}
+
+func TestParseTime_UsesClockLocation(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	tc.SetLocation(time.FixedZone("EST", -5*3600))
+
+	got, err := human.ParseTime(tc, "today")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-15T00:00:00-05:00", got.Format(time.RFC3339))
+
+	got, err = human.ParseTime(tc, "2025-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-01T00:00:00-05:00", got.Format(time.RFC3339))
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{250 * time.Millisecond, "250ms"},
+		{45 * time.Second, "45s"},
+		{90 * time.Second, "1m30s"},
+		{90 * time.Minute, "1h30m"},
+		{3*time.Hour + 20*time.Second, "3h"},
+		{52 * time.Hour, "2d4h"},
+		{human.Day + 5*time.Minute, "1d"},
+		{10 * human.Day, "1w3d"},
+		{-90 * time.Second, "-1m30s"},
+		{math.MaxInt64, "15250w1d"},
+		{math.MinInt64, "-15250w1d"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, human.FormatDuration(tt.in), tt.in.String())
+		if tt.in > 0 {
+			back, err := human.ParseDuration(human.FormatDuration(tt.in))
+			require.NoError(t, err)
+			assert.LessOrEqual(t, back, tt.in)
+		}
+	}
+}
+
+func TestFormatRelative(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{-3 * time.Second, "just now"},
+		{-time.Minute, "1 minute ago"},
+		{-5*time.Minute - 40*time.Second, "5 minutes ago"},
+		{-26 * time.Hour, "1 day ago"},
+		{-3 * human.Week, "3 weeks ago"},
+		{-400 * human.Day, "1 year ago"},
+		{2 * time.Hour, "in 2 hours"},
+		{45 * time.Second, "in 45 seconds"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, human.FormatRelative(tc, seed.Add(tt.offset)))
+	}
+
+	// Sub saturates for times centuries away.
+	far := time.Date(3000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "in 292 years", human.FormatRelative(tc, far))
+}
@@ -0,0 +1,95 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter measured by a [SchedulingClock]. The
+// bucket holds up to burst tokens and refills one token every interval. It
+// is a clock-aware replacement for golang.org/x/time/rate, so a [TestClock]
+// can drive refills with Advance. A Limiter is safe for concurrent use.
+type Limiter struct {
+	c     SchedulingClock
+	every time.Duration
+	burst int
+
+	mu sync.Mutex
+	// tat is the theoretical arrival time: the instant the bucket would be
+	// full again if no more tokens were taken. Tokens available at now are
+	// burst - (tat-now)/every.
+	tat time.Time
+}
+
+// NewLimiter returns a Limiter that starts full with burst tokens and adds
+// one token every interval. A nil c uses [OsClock]. It panics if every is not
+// positive or burst is less than 1.
+func NewLimiter(c SchedulingClock, every time.Duration, burst int) *Limiter {
+	if c == nil {
+		c = OsClock{}
+	}
+	if every <= 0 {
+		panic("clock: NewLimiter called with non-positive interval")
+	}
+	if burst < 1 {
+		panic("clock: NewLimiter called with burst < 1")
+	}
+	return &Limiter{c: c, every: every, burst: burst}
+}
+
+// Allow takes a token if one is available and reports whether it did.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.reserveLocked(l.c.Now()) > 0 {
+		l.tat = l.tat.Add(-l.every)
+		return false
+	}
+	return true
+}
+
+// Wait blocks until a token is available and takes it, or returns ctx.Err()
+// if ctx ends first. Waiters are served in the order they called Wait. A
+// cancelled wait gives its token back.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	delay := l.reserveLocked(l.c.Now())
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	if err := Sleep(ctx, l.c, delay); err != nil {
+		l.mu.Lock()
+		l.tat = l.tat.Add(-l.every)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Tokens returns the number of tokens currently available. It is negative
+// while callers are waiting.
+func (l *Limiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.c.Now()
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	return float64(l.burst) - float64(tat.Sub(now))/float64(l.every)
+}
+
+// reserveLocked takes a token at now and returns how long the caller must
+// wait before using it. Caller must hold l.mu.
+func (l *Limiter) reserveLocked(now time.Time) time.Duration {
+	if l.tat.Before(now) {
+		l.tat = now
+	}
+	l.tat = l.tat.Add(l.every)
+	return l.tat.Sub(now) - time.Duration(l.burst)*l.every
+}
@@ -0,0 +1,78 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	l := clock.NewLimiter(tc, time.Second, 3)
+
+	for range 3 {
+		assert.True(t, l.Allow())
+	}
+	assert.False(t, l.Allow(), "burst exhausted")
+	assert.InDelta(t, 0, l.Tokens(), 1e-9)
+
+	tc.Advance(500 * time.Millisecond)
+	assert.False(t, l.Allow())
+	assert.InDelta(t, 0.5, l.Tokens(), 1e-9)
+
+	tc.Advance(500 * time.Millisecond)
+	assert.True(t, l.Allow())
+	assert.False(t, l.Allow())
+
+	tc.Advance(time.Hour)
+	assert.InDelta(t, 3, l.Tokens(), 1e-9, "bucket never exceeds burst")
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	l := clock.NewLimiter(tc, time.Second, 1)
+	require.NoError(t, l.Wait(t.Context()))
+
+	done := make(chan time.Time, 2)
+	for range 2 {
+		go func() {
+			if l.Wait(t.Context()) == nil {
+				done <- tc.Now()
+			}
+		}()
+	}
+	tc.BlockUntil(2)
+	assert.InDelta(t, -2, l.Tokens(), 1e-9)
+
+	tc.Advance(time.Second)
+	assert.Equal(t, seed.Add(time.Second), <-done)
+	tc.Advance(time.Second)
+	assert.Equal(t, seed.Add(2*time.Second), <-done)
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	l := clock.NewLimiter(tc, time.Second, 1)
+	require.True(t, l.Allow())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errc := make(chan error, 1)
+	go func() { errc <- l.Wait(ctx) }()
+	tc.BlockUntil(1)
+	cancel()
+
+	assert.ErrorIs(t, <-errc, context.Canceled)
+	assert.InDelta(t, 0, l.Tokens(), 1e-9, "cancelled wait returns its token")
+	tc.Advance(time.Second)
+	assert.True(t, l.Allow())
+}
@@ -0,0 +1,106 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestClock_SyncCallbacks(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithSyncCallbacks())
+
+	// No locking: callbacks run on the goroutine calling Advance.
+	var order []string
+	var seen []time.Time
+	tc.AfterFunc(3*time.Second, func() {
+		order = append(order, "b")
+		seen = append(seen, tc.Now())
+	})
+	tc.AfterFunc(time.Second, func() {
+		order = append(order, "a")
+		seen = append(seen, tc.Now())
+		// Due before the target, so it runs in this Advance.
+		tc.AfterFunc(time.Second, func() {
+			order = append(order, "a2")
+			seen = append(seen, tc.Now())
+		})
+		// Due after the target, so it waits for the next one.
+		tc.AfterFunc(time.Minute, func() { order = append(order, "late") })
+	})
+
+	tc.Advance(5 * time.Second)
+	assert.Equal(t, []string{"a", "a2", "b"}, order)
+	assert.Equal(t, []time.Time{
+		seed.Add(time.Second), seed.Add(2 * time.Second), seed.Add(3 * time.Second),
+	}, seen)
+	assert.Equal(t, seed.Add(5*time.Second), tc.Now())
+	assert.Equal(t, 1, tc.PendingCount())
+
+	assert.Equal(t, 1, tc.RunUntilIdle())
+	assert.Equal(t, []string{"a", "a2", "b", "late"}, order)
+}
+
+func TestTestClock_SyncCallbacksStopTimers(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithSyncCallbacks())
+	var stopped bool
+	timer := tc.AfterFunc(time.Second, func() { stopped = true })
+	tc.AfterFunc(500*time.Millisecond, func() {
+		assert.True(t, timer.Stop(), "callbacks may stop other timers")
+	})
+	tc.Advance(time.Second)
+	assert.False(t, stopped)
+}
+
+func TestTestClock_DroppedTicks(t *testing.T) {
+	t.Parallel()
+
+	var drops []clock.DroppedTick
+	tc := clock.NewTestClock(seed, clock.WithDroppedTickHandler(func(d clock.DroppedTick) {
+		drops = append(drops, d)
+	}))
+	ticker := tc.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	tc.Advance(3 * time.Second)
+	assert.Equal(t, seed.Add(time.Second), <-ticker.C())
+	assert.Equal(t, 2, tc.DroppedTicks())
+	require.Len(t, drops, 2)
+	assert.Equal(t, seed.Add(2*time.Second), drops[0].At)
+	assert.Equal(t, seed.Add(3*time.Second), drops[1].At)
+}
+
+func TestTestClock_BlockingTicks(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithBlockingTicks())
+	ticker := tc.NewTicker(time.Second)
+
+	got := make(chan time.Time, 3)
+	go func() {
+		for range 3 {
+			got <- <-ticker.C()
+		}
+	}()
+	tc.Advance(3 * time.Second)
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, seed.Add(time.Duration(i)*time.Second), <-got)
+	}
+	assert.Zero(t, tc.DroppedTicks())
+
+	// Stopping the ticker releases an Advance blocked on an unread tick.
+	done := make(chan struct{})
+	go func() {
+		tc.Advance(2 * time.Second)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return len(ticker.C()) == 1 }, time.Second, time.Millisecond)
+	ticker.Stop()
+	<-done
+}
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Policy decides how long to wait before the next attempt.
+type Policy interface {
+	// Delay returns the wait after the given number of failed attempts
+	// (starting at 1). prev is the previous delay, or 0 before the first
+	// retry.
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+// Constant returns a policy that always waits d.
+func Constant(d time.Duration) Policy {
+	return constant(d)
+}
+
+type constant time.Duration
+
+func (c constant) Delay(int, time.Duration) time.Duration {
+	return time.Duration(c)
+}
+
+// Exponential returns a policy that waits base, then doubles the wait after
+// each failed attempt up to max. A max of 0 leaves the wait uncapped.
+func Exponential(base, max time.Duration) Policy {
+	return exponential{base: base, max: max}
+}
+
+type exponential struct {
+	base, max time.Duration
+}
+
+func (e exponential) Delay(attempt int, _ time.Duration) time.Duration {
+	d := e.base
+	for i := 1; i < attempt; i++ {
+		if e.max > 0 && d >= e.max {
+			break
+		}
+		if d > maxDuration/2 {
+			d = maxDuration
+			break
+		}
+		d *= 2
+	}
+	if e.max > 0 && d > e.max {
+		d = e.max
+	}
+	return d
+}
+
+const maxDuration = time.Duration(1<<63 - 1)
+
+// DecorrelatedJitter returns a policy that waits a random duration between
+// base and three times the previous wait, capped at max. Randomising each
+// wait keeps many clients that failed together from retrying in lockstep.
+// A nil r uses the math/rand/v2 global source.
+func DecorrelatedJitter(base, max time.Duration, r *rand.Rand) Policy {
+	return &decorrelated{base: base, max: max, rand: r}
+}
+
+type decorrelated struct {
+	base, max time.Duration
+	mu        sync.Mutex
+	rand      *rand.Rand
+}
+
+func (j *decorrelated) Delay(_ int, prev time.Duration) time.Duration {
+	if prev < j.base {
+		prev = j.base
+	}
+	hi := prev
+	if hi <= maxDuration/3 {
+		hi *= 3
+	}
+	d := j.base
+	if span := int64(hi - j.base); span > 0 {
+		d += time.Duration(j.int64N(span))
+	}
+	if j.max > 0 && d > j.max {
+		d = j.max
+	}
+	return d
+}
+
+func (j *decorrelated) int64N(n int64) int64 {
+	if j.rand == nil {
+		return rand.Int64N(n)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.rand.Int64N(n)
+}
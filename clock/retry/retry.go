@@ -0,0 +1,217 @@
+// Package retry runs operations again after failures, waiting between
+// attempts according to a backoff [Policy].
+//
+// Waits are measured by a [clock.SchedulingClock], so with a
+// [clock.TestClock] a test advances the clock instead of sleeping:
+//
+//	err := retry.Do(ctx, rt.SchedulingClock(), func(ctx context.Context) error {
+//		return acquireLock(ctx)
+//	},
+//		retry.WithPolicy(retry.Exponential(100*time.Millisecond, 5*time.Second)),
+//		retry.WithMaxAttempts(8),
+//		retry.OnRetry(func(a retry.Attempt) {
+//			lg.Warn("lock busy, retrying", "attempt", a.N, "wait", a.Delay, "err", a.Err)
+//		}),
+//	)
+//
+// Errors wrapped with [Permanent], or rejected by [WithRetryIf], end the
+// loop at once. When retries run out, the result is an [*Error] that wraps
+// the last failure.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+)
+
+// Defaults used when no option overrides them.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBaseDelay   = 100 * time.Millisecond
+	DefaultMaxDelay    = 10 * time.Second
+)
+
+// Attempt describes a failed attempt that is about to be retried.
+type Attempt struct {
+	// N is the number of attempts made so far, starting at 1.
+	N int
+	// Err is the error the attempt returned.
+	Err error
+	// Delay is the wait before the next attempt.
+	Delay time.Duration
+	// Elapsed is the clock time since the first attempt started.
+	Elapsed time.Duration
+}
+
+// Option configures [Do] and [DoValue].
+type Option func(*options)
+
+type options struct {
+	policy      Policy
+	maxAttempts int
+	maxElapsed  time.Duration
+	retryIf     func(error) bool
+	onRetry     func(Attempt)
+}
+
+// WithPolicy sets the backoff policy. The default is
+// Exponential(DefaultBaseDelay, DefaultMaxDelay).
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithMaxAttempts limits the total number of attempts, including the first.
+// Zero or less removes the limit. The default is DefaultMaxAttempts.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithMaxElapsed stops retrying when the next wait would end more than d
+// after the first attempt started. Zero, the default, removes the limit.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(o *options) {
+		o.maxElapsed = d
+	}
+}
+
+// WithRetryIf retries only errors for which f returns true. Other errors are
+// returned unchanged. By default every error except a [Permanent] one is
+// retried.
+func WithRetryIf(f func(error) bool) Option {
+	return func(o *options) {
+		o.retryIf = f
+	}
+}
+
+// OnRetry calls f after each failed attempt that will be retried, before
+// waiting. Use it to log retries.
+func OnRetry(f func(Attempt)) Option {
+	return func(o *options) {
+		o.onRetry = f
+	}
+}
+
+// Error reports that retrying gave up. It wraps the last error from the
+// operation and, when the context ended the retries, the context error, so
+// both errors.Is(err, context.Canceled) and checks for the operation's error
+// work.
+type Error struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+	// Elapsed is the clock time since the first attempt started.
+	Elapsed time.Duration
+	// Err is the last error returned by the operation.
+	Err error
+	// Cause is the context error when the context ended the retries, or nil
+	// when the attempt or time budget ran out.
+	Cause error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("retry: %v after %d attempts: %v", e.Cause, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("retry: gave up after %d attempts in %s: %v", e.Attempts, e.Elapsed, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Cause, e.Err}
+	}
+	return []error{e.Err}
+}
+
+// Permanent marks err as not worth retrying. Do returns the wrapped error
+// without waiting. Permanent returns nil when err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with [Permanent].
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string { return p.err.Error() }
+func (p *permanentError) Unwrap() error { return p.err }
+
+// Do calls fn until it succeeds, returns a non-retryable error, or the
+// attempt, time or context budget runs out. Waits between attempts are
+// measured by c; a nil c uses [clock.OsClock].
+func Do(ctx context.Context, c clock.SchedulingClock, fn func(ctx context.Context) error, opts ...Option) error {
+	_, err := DoValue(ctx, c, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// DoValue is like [Do] for operations that return a value.
+func DoValue[T any](ctx context.Context, c clock.SchedulingClock, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	if c == nil {
+		c = clock.OsClock{}
+	}
+	o := &options{
+		policy:      Exponential(DefaultBaseDelay, DefaultMaxDelay),
+		maxAttempts: DefaultMaxAttempts,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	var zero T
+	start := c.Now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		var p *permanentError
+		if errors.As(err, &p) {
+			return zero, p.err
+		}
+		if o.retryIf != nil && !o.retryIf(err) {
+			return zero, err
+		}
+		elapsed := c.Since(start)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return zero, &Error{Attempts: attempt, Elapsed: elapsed, Err: err, Cause: ctxErr}
+		}
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			return zero, &Error{Attempts: attempt, Elapsed: elapsed, Err: err}
+		}
+
+		delay = o.policy.Delay(attempt, delay)
+		if o.maxElapsed > 0 && elapsed+delay > o.maxElapsed {
+			return zero, &Error{Attempts: attempt, Elapsed: elapsed, Err: err}
+		}
+		if o.onRetry != nil {
+			o.onRetry(Attempt{N: attempt, Err: err, Delay: delay, Elapsed: elapsed})
+		}
+		if ctxErr := clock.Sleep(ctx, c, delay); ctxErr != nil {
+			return zero, &Error{Attempts: attempt, Elapsed: c.Since(start), Err: err, Cause: ctxErr}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/clock/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	seed    = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	errBusy = errors.New("busy")
+)
+
+// drive steps tc through every wait until done is closed.
+func drive(t *testing.T, tc *clock.TestClock, done <-chan struct{}) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for tc.BlockUntilContext(ctx, 1) == nil {
+		tc.AdvanceToNext()
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("retry loop stalled")
+	}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	var calls atomic.Int32
+	var waits []time.Duration
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err = retry.Do(t.Context(), tc, func(context.Context) error {
+			if calls.Add(1) < 4 {
+				return errBusy
+			}
+			return nil
+		},
+			retry.WithPolicy(retry.Exponential(time.Second, 3*time.Second)),
+			retry.OnRetry(func(a retry.Attempt) {
+				assert.ErrorIs(t, a.Err, errBusy)
+				waits = append(waits, a.Delay)
+			}),
+		)
+	}()
+	drive(t, tc, done)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, waits)
+	assert.Equal(t, seed.Add(6*time.Second), tc.Now())
+}
+
+func TestDo_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("max attempts", func(t *testing.T) {
+		t.Parallel()
+		tc := clock.NewTestClock(seed)
+		var err error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			err = retry.Do(t.Context(), tc, func(context.Context) error { return errBusy },
+				retry.WithPolicy(retry.Constant(time.Minute)),
+				retry.WithMaxAttempts(3))
+		}()
+		drive(t, tc, done)
+
+		var rerr *retry.Error
+		require.ErrorAs(t, err, &rerr)
+		assert.Equal(t, 3, rerr.Attempts)
+		assert.Equal(t, 2*time.Minute, rerr.Elapsed)
+		assert.ErrorIs(t, err, errBusy)
+	})
+
+	t.Run("max elapsed", func(t *testing.T) {
+		t.Parallel()
+		tc := clock.NewTestClock(seed)
+		var err error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			err = retry.Do(t.Context(), tc, func(context.Context) error { return errBusy },
+				retry.WithPolicy(retry.Constant(time.Minute)),
+				retry.WithMaxAttempts(0),
+				retry.WithMaxElapsed(150*time.Second))
+		}()
+		drive(t, tc, done)
+
+		var rerr *retry.Error
+		require.ErrorAs(t, err, &rerr)
+		assert.Equal(t, 3, rerr.Attempts)
+		assert.Nil(t, rerr.Cause)
+	})
+}
+
+func TestDo_NonRetryable(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	var calls int
+	err := retry.Do(t.Context(), tc, func(context.Context) error {
+		calls++
+		return retry.Permanent(errBusy)
+	})
+	assert.Equal(t, errBusy, err)
+	assert.Equal(t, 1, calls)
+
+	errFatal := errors.New("fatal")
+	calls = 0
+	err = retry.Do(t.Context(), tc, func(context.Context) error {
+		calls++
+		return errFatal
+	}, retry.WithRetryIf(func(err error) bool { return errors.Is(err, errBusy) }))
+	assert.Equal(t, errFatal, err)
+	assert.Equal(t, 1, calls)
+	assert.Zero(t, tc.PendingCount())
+}
+
+func TestDo_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := context.WithCancel(t.Context())
+	errc := make(chan error, 1)
+	go func() {
+		errc <- retry.Do(ctx, tc, func(context.Context) error { return errBusy })
+	}()
+	tc.BlockUntil(1)
+	cancel()
+
+	err := <-errc
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errBusy)
+	assert.Zero(t, tc.PendingCount())
+}
+
+func TestDoValue(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	var calls int
+	var got string
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		got, err = retry.DoValue(t.Context(), tc, func(context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errBusy
+			}
+			return "ok", nil
+		})
+	}()
+	drive(t, tc, done)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", got)
+}
+
+func TestPolicies(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 5*time.Second, retry.Constant(5*time.Second).Delay(10, 0))
+
+	exp := retry.Exponential(time.Second, time.Minute)
+	assert.Equal(t, time.Second, exp.Delay(1, 0))
+	assert.Equal(t, 8*time.Second, exp.Delay(4, 0))
+	assert.Equal(t, time.Minute, exp.Delay(100, 0))
+
+	j := retry.DecorrelatedJitter(time.Second, 30*time.Second, rand.New(rand.NewPCG(1, 2)))
+	var prev time.Duration
+	for i := 1; i <= 50; i++ {
+		d := j.Delay(i, prev)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 30*time.Second)
+		assert.LessOrEqual(t, d, 3*max(prev, time.Second))
+		prev = d
+	}
+}
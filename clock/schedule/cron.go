@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a schedule parsed from a five-field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Fields accept "*", values, ranges ("1-5"), lists ("1,15") and steps ("*/15",
+// "0-30/10"). Months and weekdays also accept three-letter names ("JAN",
+// "MON"), and day-of-week 7 means Sunday. As in Vixie cron, when both
+// day-of-month and day-of-week are restricted, a day matching either runs.
+//
+// Times are computed on the wall clock of the location, so "0 3 * * *" runs at
+// 03:00 local time across DST changes. Around transitions Cron follows Vixie
+// cron: a job with a fixed hour whose time is skipped by a spring-forward
+// change runs at the first instant after the gap, and one whose time repeats
+// on a fall-back change runs only once. Jobs with a wildcard hour run by
+// elapsed time through both.
+type Cron struct {
+	expr   string
+	minute uint64 // bits 0-59
+	hour   uint64 // bits 0-23
+	dom    uint64 // bits 1-31
+	month  uint64 // bits 1-12
+	dow    uint64 // bits 0-6
+
+	domStar, dowStar, hourStar bool
+
+	loc *time.Location
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// ParseCron parses a cron expression or one of the macros @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly. Fire times are
+// computed in loc, or in the location of the time passed to Next when loc is
+// nil.
+func ParseCron(expr string, loc *time.Location) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if m, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: cron %q: want 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{expr: expr, loc: loc}
+	var err error
+	parse := func(field string, lo, hi int, names map[string]int) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseField(field, lo, hi, names)
+		if err != nil {
+			err = fmt.Errorf("schedule: cron %q: %w", expr, err)
+		}
+		return bits
+	}
+	c.minute = parse(fields[0], 0, 59, nil)
+	c.hour = parse(fields[1], 0, 23, nil)
+	c.dom = parse(fields[2], 1, 31, nil)
+	c.month = parse(fields[3], 1, 12, monthNames)
+	c.dow = parse(fields[4], 0, 7, dayNames)
+	if err != nil {
+		return nil, err
+	}
+	// Fold 7 into Sunday.
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.hourStar = fields[1] == "*"
+	c.domStar = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	c.dowStar = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+	return c, nil
+}
+
+// parseField parses one comma-separated cron field into a bit set.
+func parseField(field string, lo, hi int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = lo, hi
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = fieldValue(a, names); err != nil {
+				return 0, err
+			}
+			if end, err = fieldValue(b, names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := fieldValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			start, end = v, v
+			if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func fieldValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *Cron) String() string { return c.expr }
+
+// maxSearch bounds how far Next looks for a match, so expressions such as
+// "0 0 30 2 *" that never match terminate.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first fire time strictly after t, or the zero time when
+// the expression never matches.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := c.loc
+	if loc == nil {
+		loc = t.Location()
+	}
+	t = t.In(loc)
+	limit := t.Add(maxSearch)
+
+	// Start at the next whole minute.
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if at, ok := c.afterGap(t); ok {
+			return at
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			// Step by elapsed time so DST changes neither skip nor repeat
+			// an hour of the search.
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		if !c.hourStar && repeatedWallTime(t) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// afterGap reports whether t is the first instant after a spring-forward gap
+// that skipped a wall time this fixed-hour schedule wanted to run at.
+func (c *Cron) afterGap(t time.Time) (time.Time, bool) {
+	if c.hourStar {
+		return time.Time{}, false
+	}
+	_, offNow := t.Zone()
+	_, offPrev := t.Add(-time.Minute).Zone()
+	gap := time.Duration(offNow-offPrev) * time.Second
+	if gap <= 0 {
+		return time.Time{}, false
+	}
+	// The wall times [t-gap, t) did not exist today.
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	for w := wall.Add(-gap); w.Before(wall); w = w.Add(time.Minute) {
+		if c.hour&(1<<uint(w.Hour())) != 0 && c.minute&(1<<uint(w.Minute())) != 0 {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// repeatedWallTime reports whether the wall time of t already occurred
+// earlier, during a fall-back change.
+func repeatedWallTime(t time.Time) bool {
+	_, offNow := t.Zone()
+	for _, back := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour} {
+		earlier := t.Add(-back)
+		_, off := earlier.Zone()
+		if time.Duration(off-offNow)*time.Second == back &&
+			earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+)
+
+// Job runs a function at the fire times of a schedule. Create one with
+// [Start].
+type Job struct {
+	c  clock.SchedulingClock
+	s  Schedule
+	fn func(ctx context.Context, at time.Time)
+	// jitter is set when s is a [Jitter] schedule. The job then advances
+	// through the unjittered times and delays each run separately, so the
+	// jitter does not accumulate.
+	jitter *jittered
+
+	ctx     context.Context
+	running sync.Mutex
+
+	mu      sync.Mutex
+	timer   clock.Timer
+	next    time.Time
+	stopped bool
+	stop    func() bool
+}
+
+// Start schedules fn to run at each fire time of s, measured by c. fn
+// receives ctx and the scheduled fire time. The job stops when ctx ends or
+// [Job.Stop] is called.
+//
+// Each timer is registered with c.AfterFunc before fn runs, so exactly one
+// timer is pending while the job is active. Runs never overlap: a fire time
+// that arrives while fn is still running is skipped. Fire times missed while
+// the clock was not advancing are not replayed; the job resumes at the next
+// fire time after the current clock time.
+func Start(ctx context.Context, c clock.SchedulingClock, s Schedule, fn func(ctx context.Context, at time.Time)) *Job {
+	if c == nil {
+		c = clock.OsClock{}
+	}
+	j := &Job{c: c, s: s, fn: fn, ctx: ctx}
+	if js, ok := s.(*jittered); ok {
+		j.s, j.jitter = js.s, js
+	}
+	j.mu.Lock()
+	j.scheduleLocked(c.Now())
+	j.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, j.Stop)
+	j.mu.Lock()
+	j.stop = stop
+	j.mu.Unlock()
+	return j
+}
+
+// scheduleLocked arms the timer for the first fire time after from. Caller
+// must hold j.mu.
+func (j *Job) scheduleLocked(from time.Time) {
+	if j.stopped {
+		return
+	}
+	now := j.c.Now()
+	base := j.s.Next(from)
+	if !base.IsZero() && !base.After(now) {
+		base = j.s.Next(now)
+	}
+	if base.IsZero() {
+		j.next = base
+		j.timer = nil
+		return
+	}
+	next := base
+	if j.jitter != nil {
+		next = base.Add(j.jitter.offset())
+	}
+	j.next = next
+	j.timer = j.c.AfterFunc(next.Sub(now), func() { j.fire(base, next) })
+}
+
+// fire runs fn for the fire time at, after scheduling the run that follows
+// base, the unjittered time at was derived from.
+func (j *Job) fire(base, at time.Time) {
+	j.mu.Lock()
+	if j.stopped {
+		j.mu.Unlock()
+		return
+	}
+	j.scheduleLocked(base)
+	j.mu.Unlock()
+
+	if !j.running.TryLock() {
+		return
+	}
+	defer j.running.Unlock()
+	j.fn(j.ctx, at)
+}
+
+// Next returns the next fire time, or the zero time when the schedule is
+// exhausted or the job is stopped.
+func (j *Job) Next() time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.stopped {
+		return time.Time{}
+	}
+	return j.next
+}
+
+// Stop cancels future runs. A run in progress is not interrupted.
+func (j *Job) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.stopped {
+		return
+	}
+	j.stopped = true
+	if j.timer != nil {
+		j.timer.Stop()
+	}
+	if j.stop != nil {
+		j.stop()
+	}
+}
@@ -0,0 +1,157 @@
+// Package schedule computes fire times from cron expressions and jittered
+// intervals and runs jobs on a [clock.SchedulingClock].
+//
+// Schedules are parsed with [Parse]:
+//
+//	s, err := schedule.Parse("0 3 * * *", schedule.WithLocation(loc))
+//	s, err := schedule.Parse("@every 6h jitter 30m")
+//
+// [Start] runs a function at each fire time using the clock's AfterFunc, so a
+// [clock.TestClock] can step through days of a schedule with
+// [clock.TestClock.AdvanceToNext] instead of sleeping.
+package schedule
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule computes fire times.
+type Schedule interface {
+	// Next returns the first fire time strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+// Option configures [Parse].
+type Option func(*options)
+
+type options struct {
+	loc  *time.Location
+	rand *rand.Rand
+}
+
+// WithLocation computes cron fire times on the wall clock of loc. Without it
+// the location of the time passed to Next is used.
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.loc = loc
+	}
+}
+
+// WithRand sets the random source for jitter, so tests can make jitter
+// deterministic.
+func WithRand(r *rand.Rand) Option {
+	return func(o *options) {
+		o.rand = r
+	}
+}
+
+// Parse parses a schedule spec. A spec is one of:
+//
+//   - a five-field cron expression or macro, as accepted by [ParseCron]
+//   - "@every D", a fixed interval such as "@every 1h30m"
+//
+// Either form may end in "jitter D" to delay each fire time by a random
+// amount in [0, D), spreading load from many machines.
+func Parse(spec string, opts ...Option) (Schedule, error) {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	body := strings.TrimSpace(spec)
+	var jitter time.Duration
+	if i := strings.LastIndex(body, " jitter "); i >= 0 {
+		d, err := time.ParseDuration(strings.TrimSpace(body[i+len(" jitter "):]))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("schedule: %q: invalid jitter", spec)
+		}
+		jitter = d
+		body = strings.TrimSpace(body[:i])
+	}
+
+	var s Schedule
+	if rest, ok := strings.CutPrefix(body, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("schedule: %q: invalid interval", spec)
+		}
+		s = Every(d)
+	} else {
+		c, err := ParseCron(body, o.loc)
+		if err != nil {
+			return nil, err
+		}
+		s = c
+	}
+	if jitter > 0 {
+		s = Jitter(s, jitter, o.rand)
+	}
+	return s, nil
+}
+
+// Interval fires every Period.
+type Interval struct {
+	Period time.Duration
+}
+
+// Every returns an Interval schedule. It panics if d is not positive.
+func Every(d time.Duration) Interval {
+	if d <= 0 {
+		panic("schedule: Every called with non-positive interval")
+	}
+	return Interval{Period: d}
+}
+
+// Next returns t + Period.
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(i.Period)
+}
+
+func (i Interval) String() string {
+	return "@every " + i.Period.String()
+}
+
+// Jitter returns s with each fire time delayed by a random duration in
+// [0, max). A nil r uses the math/rand/v2 global source.
+func Jitter(s Schedule, max time.Duration, r *rand.Rand) Schedule {
+	return &jittered{s: s, max: max, rand: r}
+}
+
+type jittered struct {
+	s    Schedule
+	max  time.Duration
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (j *jittered) Next(t time.Time) time.Time {
+	next := j.s.Next(t)
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(j.offset())
+}
+
+// offset returns a random delay in [0, max).
+func (j *jittered) offset() time.Duration {
+	if j.max <= 0 {
+		return 0
+	}
+	if j.rand == nil {
+		return time.Duration(rand.Int64N(int64(j.max)))
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return time.Duration(j.rand.Int64N(int64(j.max)))
+}
+
+func (j *jittered) String() string {
+	return fmt.Sprintf("%v jitter %s", j.s, j.max)
+}
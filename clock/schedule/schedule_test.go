@@ -0,0 +1,195 @@
+package schedule_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/clock/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var seed = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func mustCron(t *testing.T, expr string, loc *time.Location) *schedule.Cron {
+	t.Helper()
+	c, err := schedule.ParseCron(expr, loc)
+	require.NoError(t, err)
+	return c
+}
+
+func TestCron_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"*/15 * * * *", seed, seed.Add(15 * time.Minute)},
+		{"0 3 * * *", seed, time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", seed, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", seed, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", seed, time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 FEB *", seed, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day-of-month OR day-of-week when both are restricted.
+		{"0 0 15 * FRI", seed, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", seed, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, mustCron(t, tt.expr, nil).Next(tt.after))
+		})
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "* * * FOO *"} {
+		_, err := schedule.ParseCron(expr, nil)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCron_DST(t *testing.T) {
+	t.Parallel()
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("fixed time stays on local wall clock", func(t *testing.T) {
+		c := mustCron(t, "0 3 * * *", ny)
+		got := c.Next(time.Date(2025, 3, 8, 12, 0, 0, 0, ny))
+		assert.Equal(t, time.Date(2025, 3, 9, 3, 0, 0, 0, ny), got)
+		_, off := got.Zone()
+		assert.Equal(t, -4*3600, off)
+	})
+
+	t.Run("skipped time runs after the gap", func(t *testing.T) {
+		c := mustCron(t, "30 2 * * *", ny)
+		got := c.Next(time.Date(2025, 3, 8, 12, 0, 0, 0, ny))
+		assert.Equal(t, time.Date(2025, 3, 9, 3, 0, 0, 0, ny), got)
+		assert.Equal(t, time.Date(2025, 3, 10, 2, 30, 0, 0, ny), c.Next(got))
+	})
+
+	t.Run("repeated time runs once", func(t *testing.T) {
+		c := mustCron(t, "30 1 * * *", ny)
+		first := c.Next(time.Date(2025, 11, 1, 12, 0, 0, 0, ny))
+		assert.Equal(t, "2025-11-02T01:30:00-04:00", first.Format(time.RFC3339))
+		assert.Equal(t, time.Date(2025, 11, 3, 1, 30, 0, 0, ny), c.Next(first))
+	})
+
+	t.Run("wildcard hour runs by elapsed time", func(t *testing.T) {
+		c := mustCron(t, "30 * * * *", ny)
+		first := c.Next(time.Date(2025, 11, 2, 1, 0, 0, 0, ny))
+		second := c.Next(first)
+		assert.Equal(t, time.Hour, second.Sub(first))
+		assert.Equal(t, first.Hour(), second.Hour())
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	s, err := schedule.Parse("@every 90m")
+	require.NoError(t, err)
+	assert.Equal(t, seed.Add(90*time.Minute), s.Next(seed))
+
+	r := rand.New(rand.NewPCG(1, 2))
+	s, err = schedule.Parse("@every 1h jitter 10m", schedule.WithRand(r))
+	require.NoError(t, err)
+	for range 20 {
+		next := s.Next(seed)
+		assert.GreaterOrEqual(t, next.Sub(seed), time.Hour)
+		assert.Less(t, next.Sub(seed), time.Hour+10*time.Minute)
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	s, err = schedule.Parse("0 9 * * *", schedule.WithLocation(ny))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 9, 0, 0, 0, ny), s.Next(seed))
+
+	for _, spec := range []string{"@every", "@every -1h", "@every 1h jitter x", "bogus"} {
+		_, err := schedule.Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestStart_FastForwardsWithTestClock(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	s := mustCron(t, "0 3 * * *", nil)
+
+	var mu sync.Mutex
+	var runs []time.Time
+	job := schedule.Start(t.Context(), tc, s, func(_ context.Context, at time.Time) {
+		mu.Lock()
+		runs = append(runs, at)
+		mu.Unlock()
+	})
+	defer job.Stop()
+	assert.Equal(t, time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC), job.Next())
+
+	for range 7 {
+		tc.BlockUntil(1)
+		require.True(t, tc.AdvanceToNext())
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(runs) == 7
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, at := range runs {
+		assert.Equal(t, time.Date(2025, 1, 1+i, 3, 0, 0, 0, time.UTC), at)
+	}
+}
+
+func TestStart_StopsWithContext(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	ctx, cancel := context.WithCancel(t.Context())
+	job := schedule.Start(ctx, tc, schedule.Every(time.Minute), func(context.Context, time.Time) {})
+	assert.Equal(t, 1, tc.PendingCount())
+
+	cancel()
+	require.Eventually(t, func() bool { return tc.PendingCount() == 0 }, time.Second, time.Millisecond)
+	assert.True(t, job.Next().IsZero())
+}
+
+func TestStart_JitterDoesNotDrift(t *testing.T) {
+	t.Parallel()
+
+	const period, jitter = time.Hour, 10 * time.Minute
+	tc := clock.NewTestClock(seed, clock.WithSyncCallbacks())
+	s, err := schedule.Parse("@every 1h jitter 10m", schedule.WithRand(rand.New(rand.NewPCG(3, 4))))
+	require.NoError(t, err)
+
+	var runs []time.Time
+	job := schedule.Start(t.Context(), tc, s, func(_ context.Context, at time.Time) {
+		runs = append(runs, at)
+	})
+	defer job.Stop()
+
+	tc.Advance(100*period + jitter)
+	require.Len(t, runs, 100)
+	for i, at := range runs {
+		base := seed.Add(time.Duration(i+1) * period)
+		assert.False(t, at.Before(base), "run %d at %s", i, at)
+		assert.True(t, at.Before(base.Add(jitter)), "run %d at %s", i, at)
+	}
+}
@@ -2,6 +2,7 @@ package clock
 
 import (
 	"container/heap"
+	"context"
 	"sync"
 	"time"
 )
@@ -21,9 +22,13 @@ const (
 // prevents all future firings even after the current heap entry was replaced
 // by re-insertion.
 type tickerState struct {
+	// id is the ID of the first entry, used to name the ticker in traces.
+	id      uint64
 	stopped bool
 	ch      chan time.Time
 	period  time.Duration
+	// done is closed by Stop so a blocking tick send gives up.
+	done chan struct{}
 }
 
 // schedEntry is a single scheduled event managed by the min-heap.
@@ -137,6 +142,35 @@ type scheduler struct {
 	now     *time.Time // pointer to TestClock.now — read under scheduler.mu
 	nextID  uint64
 	entries schedHeap
+
+	// inflight counts AfterFunc callbacks that have been started but have
+	// not returned.
+	inflight int
+
+	// waiters are blocked in wait until their condition holds.
+	waiters []*schedWaiter
+
+	// loc, when set, is the location reported times are converted to.
+	loc *time.Location
+
+	// tracing enables recording of events; see TestClock.StartTrace.
+	tracing bool
+	events  []TraceEvent
+
+	// Modes set by TestClockOptions before the clock is used.
+	syncCallbacks bool
+	blockingTicks bool
+	onDrop        func(DroppedTick)
+
+	// dropped counts ticks dropped on a full channel.
+	dropped int
+}
+
+// schedWaiter is a goroutine blocked until ready reports true. ready is
+// evaluated with scheduler.mu held.
+type schedWaiter struct {
+	ready func() bool
+	ch    chan struct{}
 }
 
 func newScheduler(now *time.Time) *scheduler {
@@ -145,6 +179,14 @@ func newScheduler(now *time.Time) *scheduler {
 	return s
 }
 
+// inLocked converts t to the scheduler location. Caller must hold s.mu.
+func (s *scheduler) inLocked(t time.Time) time.Time {
+	if s.loc == nil {
+		return t
+	}
+	return t.In(s.loc)
+}
+
 func (s *scheduler) allocID() uint64 {
 	s.nextID++
 	return s.nextID
@@ -156,6 +198,7 @@ func (s *scheduler) addTicker(period time.Duration) *tickerState {
 	ts := &tickerState{
 		ch:     make(chan time.Time, 1),
 		period: period,
+		done:   make(chan struct{}),
 	}
 	e := &schedEntry{
 		deadline: s.now.Add(period),
@@ -163,7 +206,10 @@ func (s *scheduler) addTicker(period time.Duration) *tickerState {
 		kind:     kindTicker,
 		ticker:   ts,
 	}
+	ts.id = e.id
 	heap.Push(&s.entries, e)
+	s.traceLocked(TraceRegister, e, 0)
+	s.notifyLocked()
 	return ts
 }
 
@@ -177,6 +223,8 @@ func (s *scheduler) addAfterFunc(d time.Duration, f func()) *schedEntry {
 		fn:       f,
 	}
 	heap.Push(&s.entries, e)
+	s.traceLocked(TraceRegister, e, 0)
+	s.notifyLocked()
 	return e
 }
 
@@ -190,6 +238,8 @@ func (s *scheduler) addAfter(d time.Duration) *schedEntry {
 		afterC:   make(chan time.Time, 1),
 	}
 	heap.Push(&s.entries, e)
+	s.traceLocked(TraceRegister, e, 0)
+	s.notifyLocked()
 	return e
 }
 
@@ -198,7 +248,15 @@ func (s *scheduler) addAfter(d time.Duration) *schedEntry {
 func (s *scheduler) stopTicker(ts *tickerState) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if ts.stopped {
+		return
+	}
+	if s.tracing {
+		s.recordLocked(TraceEvent{Op: TraceStop, Kind: TraceTicker, ID: ts.id, At: *s.now})
+	}
 	ts.stopped = true
+	close(ts.done)
+	s.notifyLocked()
 }
 
 // remove marks an entry as dead (tombstone). The entry will be skipped when
@@ -207,10 +265,12 @@ func (s *scheduler) remove(e *schedEntry) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	e.dead = true
+	s.notifyLocked()
 }
 
 // cancel marks a timer entry as dead and returns true if the timer had not yet
-// fired. Returns false if the entry was already dead.
+// fired. Returns false, recording no trace event, if the entry had already
+// fired or been stopped.
 func (s *scheduler) cancel(e *schedEntry) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -218,6 +278,8 @@ func (s *scheduler) cancel(e *schedEntry) bool {
 		return false
 	}
 	e.dead = true
+	s.traceLocked(TraceStop, e, 0)
+	s.notifyLocked()
 	return true
 }
 
@@ -244,6 +306,8 @@ func (s *scheduler) resetTimer(e *schedEntry, d time.Duration) (*schedEntry, boo
 		afterC:   e.afterC,
 	}
 	heap.Push(&s.entries, fresh)
+	s.traceLocked(TraceReset, fresh, e.id)
+	s.notifyLocked()
 	return fresh, wasActive
 }
 
@@ -252,9 +316,13 @@ func (s *scheduler) resetTimer(e *schedEntry, d time.Duration) (*schedEntry, boo
 // before sending on channels or launching goroutines (to avoid deadlocks when
 // a goroutine calls Stop/Reset).
 //
-// Returns a slice of entries to fire, in deadline order, after releasing the
-// mutex.
+// With synchronous callbacks enabled it delegates to advanceSync instead.
 func (s *scheduler) advance(d time.Duration) {
+	if s.syncCallbacks {
+		s.advanceSync(d)
+		return
+	}
+
 	s.mu.Lock()
 	newNow := s.now.Add(d)
 
@@ -265,19 +333,76 @@ func (s *scheduler) advance(d time.Duration) {
 	}
 	var fires []toFire
 
+	for {
+		top := s.popDueLocked(newNow)
+		if top == nil {
+			break
+		}
+		fires = append(fires, toFire{entry: top, fireTime: s.inLocked(top.deadline)})
+	}
+
+	*s.now = newNow
+	s.notifyLocked()
+	s.mu.Unlock()
+
+	// Fire outside the lock.
+	for _, f := range fires {
+		s.fire(f.entry, f.fireTime, false)
+	}
+}
+
+// advanceSync is advance for synchronous callbacks. It fires due entries one
+// at a time, moving the clock to each deadline first and running AfterFunc
+// callbacks inline, so entries registered by a callback that fall due before
+// the target time fire in the same call.
+func (s *scheduler) advanceSync(d time.Duration) {
+	s.mu.Lock()
+	target := s.now.Add(d)
+	for {
+		top := s.popDueLocked(target)
+		if top == nil {
+			break
+		}
+		// A callback may have advanced the clock past this deadline already.
+		if top.deadline.After(*s.now) {
+			*s.now = top.deadline
+		}
+		fireTime := s.inLocked(top.deadline)
+		s.notifyLocked()
+		s.mu.Unlock()
+		s.fire(top, fireTime, true)
+		s.mu.Lock()
+	}
+	if target.After(*s.now) {
+		*s.now = target
+	}
+	s.notifyLocked()
+	s.mu.Unlock()
+}
+
+// popDueLocked removes and returns the earliest live entry due at or before
+// limit, or nil when there is none. It records the fire in the trace, counts
+// AfterFunc callbacks as in flight and re-inserts tickers for their next
+// tick. Caller must hold s.mu.
+func (s *scheduler) popDueLocked(limit time.Time) *schedEntry {
 	for len(s.entries) > 0 {
 		top := s.entries[0]
-		if top.deadline.After(newNow) {
-			break
+		if top.deadline.After(limit) {
+			return nil
 		}
 		heap.Pop(&s.entries)
-		if top.dead {
+		if !top.live() {
 			continue
 		}
-		if top.kind == kindTicker && top.ticker.stopped {
-			continue
+		s.traceLocked(TraceFire, top, 0)
+		if top.kind != kindTicker {
+			// A one-shot timer is spent once it fires, so a later Stop
+			// reports false and is not traced.
+			top.dead = true
+		}
+		if top.kind == kindAfterFunc {
+			s.inflight++
 		}
-		fires = append(fires, toFire{entry: top, fireTime: top.deadline})
 
 		// Re-insert tickers immediately so subsequent Advance calls see them.
 		if top.kind == kindTicker {
@@ -289,31 +414,169 @@ func (s *scheduler) advance(d time.Duration) {
 			}
 			heap.Push(&s.entries, next)
 		}
+		return top
 	}
+	return nil
+}
 
-	*s.now = newNow
+// fire delivers a due entry. Caller must not hold s.mu. AfterFunc callbacks
+// run inline when inline is set and in a new goroutine otherwise.
+func (s *scheduler) fire(e *schedEntry, at time.Time, inline bool) {
+	switch e.kind {
+	case kindTicker:
+		s.sendTick(e.ticker, at)
+	case kindAfterFunc:
+		if inline {
+			defer s.callbackDone()
+			e.fn()
+			return
+		}
+		go func() {
+			defer s.callbackDone()
+			e.fn()
+		}()
+	case kindAfter:
+		// Non-blocking send: the channel is buffered(1) so this should
+		// always succeed unless Reset re-used the channel and it was already
+		// read before a second fire.
+		select {
+		case e.afterC <- at:
+		default:
+		}
+	}
+}
+
+// sendTick delivers a tick. By default the send is non-blocking and a tick
+// is dropped when the buffer is full (matching stdlib); with blocking ticks
+// it waits for the receiver or for the ticker to stop.
+func (s *scheduler) sendTick(ts *tickerState, at time.Time) {
+	if s.blockingTicks {
+		select {
+		case ts.ch <- at:
+		case <-ts.done:
+		}
+		return
+	}
+	select {
+	case ts.ch <- at:
+		return
+	default:
+	}
+
+	s.mu.Lock()
+	s.dropped++
+	if s.tracing {
+		s.recordLocked(TraceEvent{Op: TraceDrop, Kind: TraceTicker, ID: ts.id, At: at})
+	}
+	onDrop := s.onDrop
 	s.mu.Unlock()
+	if onDrop != nil {
+		onDrop(DroppedTick{ID: ts.id, At: at})
+	}
+}
 
-	// Fire outside the lock.
-	for _, f := range fires {
-		switch f.entry.kind {
-		case kindTicker:
-			// Non-blocking send: drop on full buffer (matches stdlib).
-			select {
-			case f.entry.ticker.ch <- f.fireTime:
-			default:
-			}
-		case kindAfterFunc:
-			fn := f.entry.fn
-			go fn()
-		case kindAfter:
-			// Non-blocking send: the channel is buffered(1) so this should
-			// always succeed unless Reset re-used the channel and it was already
-			// read before a second fire.
-			select {
-			case f.entry.afterC <- f.fireTime:
-			default:
+// live reports whether e will still fire. Caller must hold s.mu.
+func (e *schedEntry) live() bool {
+	if e.dead {
+		return false
+	}
+	return e.kind != kindTicker || !e.ticker.stopped
+}
+
+// pendingLocked counts live entries: timers that have not fired or been
+// stopped, and running tickers. Caller must hold s.mu.
+func (s *scheduler) pendingLocked() int {
+	n := 0
+	for _, e := range s.entries {
+		if e.live() {
+			n++
+		}
+	}
+	return n
+}
+
+// nextLocked returns the earliest live entry, skipping tickers when
+// timersOnly is set. Dead entries at the top of the heap are discarded.
+// Caller must hold s.mu.
+func (s *scheduler) nextLocked(timersOnly bool) *schedEntry {
+	for len(s.entries) > 0 && !s.entries[0].live() {
+		heap.Pop(&s.entries)
+	}
+	var next *schedEntry
+	for _, e := range s.entries {
+		if !e.live() || (timersOnly && e.kind == kindTicker) {
+			continue
+		}
+		if next == nil || e.deadline.Before(next.deadline) ||
+			(e.deadline.Equal(next.deadline) && e.id < next.id) {
+			next = e
+		}
+	}
+	return next
+}
+
+// advanceToNext advances to the deadline of the next live entry and reports
+// whether there was one.
+func (s *scheduler) advanceToNext(timersOnly bool) bool {
+	s.mu.Lock()
+	next := s.nextLocked(timersOnly)
+	if next == nil {
+		s.mu.Unlock()
+		return false
+	}
+	d := max(next.deadline.Sub(*s.now), 0)
+	s.mu.Unlock()
+	s.advance(d)
+	return true
+}
+
+// callbackDone records that an AfterFunc callback returned.
+func (s *scheduler) callbackDone() {
+	s.mu.Lock()
+	s.inflight--
+	s.notifyLocked()
+	s.mu.Unlock()
+}
+
+// notifyLocked releases waiters whose condition now holds. Caller must hold
+// s.mu.
+func (s *scheduler) notifyLocked() {
+	kept := s.waiters[:0]
+	for _, w := range s.waiters {
+		if w.ready() {
+			close(w.ch)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	clear(s.waiters[len(kept):])
+	s.waiters = kept
+}
+
+// wait blocks until ready reports true or ctx ends. ready is evaluated with
+// s.mu held whenever the scheduler state changes.
+func (s *scheduler) wait(ctx context.Context, ready func() bool) error {
+	s.mu.Lock()
+	if ready() {
+		s.mu.Unlock()
+		return nil
+	}
+	w := &schedWaiter{ready: ready, ch: make(chan struct{})}
+	s.waiters = append(s.waiters, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ch:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for i, other := range s.waiters {
+			if other == w {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				break
 			}
 		}
+		s.mu.Unlock()
+		return ctx.Err()
 	}
 }
@@ -0,0 +1,321 @@
+package clock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stopwatch measures the phases of a command with laps and named, nested
+// spans. It reads time from a [Clock]: [OsClock] readings carry Go's
+// monotonic clock, so durations are immune to wall-clock jumps, and a
+// [TestClock] makes timing reports deterministic. A Stopwatch is safe for
+// concurrent use.
+//
+//	sw := clock.NewStopwatch(rt.Clock(), clock.WithSpanLogger(rt.Logger()))
+//	load := sw.Start("load config")
+//	...
+//	load.End()
+//	if timings {
+//		sw.WriteSummary(rt.Stream().Err)
+//	}
+type Stopwatch struct {
+	c     Clock
+	lg    *slog.Logger
+	start time.Time
+
+	mu    sync.Mutex
+	laps  []Lap
+	spans []*Span // top-level spans
+	open  []*Span // spans opened with Stopwatch.Start, innermost last
+}
+
+// Lap is a checkpoint recorded by [Stopwatch.Lap].
+type Lap struct {
+	// Name labels the lap.
+	Name string
+	// At is the time since the stopwatch started.
+	At time.Duration
+	// Duration is the time since the previous lap, or since the start for
+	// the first lap.
+	Duration time.Duration
+}
+
+// StopwatchOption configures [NewStopwatch].
+type StopwatchOption func(*Stopwatch)
+
+// WithSpanLogger logs every finished span to lg at debug level with its
+// slash-separated path ("span") and duration ("duration").
+func WithSpanLogger(lg *slog.Logger) StopwatchOption {
+	return func(s *Stopwatch) {
+		s.lg = lg
+	}
+}
+
+// NewStopwatch returns a Stopwatch started at c.Now(). A nil c uses the
+// default clock.
+func NewStopwatch(c Clock, opts ...StopwatchOption) *Stopwatch {
+	c = OrDefault(c)
+	s := &Stopwatch{c: c, start: c.Now()}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Elapsed returns the time since the stopwatch started.
+func (s *Stopwatch) Elapsed() time.Duration {
+	return s.c.Now().Sub(s.start)
+}
+
+// Lap records a checkpoint and returns the time since the previous one.
+func (s *Stopwatch) Lap(name string) time.Duration {
+	at := s.Elapsed()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := time.Duration(0)
+	if n := len(s.laps); n > 0 {
+		prev = s.laps[n-1].At
+	}
+	lap := Lap{Name: name, At: at, Duration: at - prev}
+	s.laps = append(s.laps, lap)
+	return lap.Duration
+}
+
+// Laps returns the recorded laps in order.
+func (s *Stopwatch) Laps() []Lap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Lap(nil), s.laps...)
+}
+
+// Start opens a span named name inside the innermost span opened with
+// Start that has not ended, or at the top level. Use [Span.Start] to nest
+// explicitly when phases run on several goroutines.
+func (s *Stopwatch) Start(name string) *Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var parent *Span
+	if n := len(s.open); n > 0 {
+		parent = s.open[n-1]
+	}
+	sp := s.newSpanLocked(parent, name)
+	s.open = append(s.open, sp)
+	return sp
+}
+
+// Time runs fn inside a span named name and returns fn's error.
+func (s *Stopwatch) Time(name string, fn func() error) error {
+	sp := s.Start(name)
+	defer sp.End()
+	return fn()
+}
+
+// Spans returns the top-level spans in the order they started.
+func (s *Stopwatch) Spans() []*Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Span(nil), s.spans...)
+}
+
+func (s *Stopwatch) newSpanLocked(parent *Span, name string) *Span {
+	sp := &Span{sw: s, parent: parent, name: name, start: s.c.Now()}
+	if parent == nil {
+		s.spans = append(s.spans, sp)
+	} else {
+		parent.children = append(parent.children, sp)
+	}
+	return sp
+}
+
+// Span is a named, timed phase recorded by a [Stopwatch].
+type Span struct {
+	sw       *Stopwatch
+	parent   *Span
+	name     string
+	start    time.Time
+	end      time.Time
+	ended    bool
+	children []*Span
+}
+
+// Name returns the span name.
+func (sp *Span) Name() string { return sp.name }
+
+// Path returns the names from the top-level span down to sp, joined by "/".
+func (sp *Span) Path() string {
+	if sp.parent == nil {
+		return sp.name
+	}
+	return sp.parent.Path() + "/" + sp.name
+}
+
+// Start opens a child span of sp.
+func (sp *Span) Start(name string) *Span {
+	sp.sw.mu.Lock()
+	defer sp.sw.mu.Unlock()
+	return sp.sw.newSpanLocked(sp, name)
+}
+
+// End stops the span, and any of its children still open, and returns its
+// duration. Ending a span twice returns the first duration.
+func (sp *Span) End() time.Duration {
+	s := sp.sw
+	now := s.c.Now()
+	s.mu.Lock()
+	if sp.ended {
+		d := sp.end.Sub(sp.start)
+		s.mu.Unlock()
+		return d
+	}
+	var finished []*Span
+	sp.endLocked(now, &finished)
+	// Drop sp and anything opened inside it from the Start stack.
+	for i, o := range s.open {
+		if o == sp {
+			s.open = s.open[:i]
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if s.lg != nil {
+		for _, f := range finished {
+			s.lg.LogAttrs(context.Background(), slog.LevelDebug, "span finished",
+				slog.String("span", f.Path()),
+				slog.Duration("duration", f.end.Sub(f.start)))
+		}
+	}
+	return sp.end.Sub(sp.start)
+}
+
+// endLocked ends sp and its open children, children first, appending each
+// to finished. Caller must hold the stopwatch lock.
+func (sp *Span) endLocked(now time.Time, finished *[]*Span) {
+	for _, c := range sp.children {
+		if !c.ended {
+			c.endLocked(now, finished)
+		}
+	}
+	sp.end = now
+	sp.ended = true
+	*finished = append(*finished, sp)
+}
+
+// Duration returns the span's duration, or the time since it started while
+// it is still open.
+func (sp *Span) Duration() time.Duration {
+	s := sp.sw
+	now := s.c.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sp.durationLocked(now)
+}
+
+func (sp *Span) durationLocked(now time.Time) time.Duration {
+	if sp.ended {
+		return sp.end.Sub(sp.start)
+	}
+	return now.Sub(sp.start)
+}
+
+// Children returns the child spans in the order they started.
+func (sp *Span) Children() []*Span {
+	sp.sw.mu.Lock()
+	defer sp.sw.mu.Unlock()
+	return append([]*Span(nil), sp.children...)
+}
+
+// LogValue reports the span durations, keyed by span path, and the total
+// elapsed time, so a Stopwatch can be passed to slog directly.
+func (s *Stopwatch) LogValue() slog.Value {
+	now := s.c.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attrs := []slog.Attr{slog.Duration("total", now.Sub(s.start))}
+	var walk func(spans []*Span)
+	walk = func(spans []*Span) {
+		for _, sp := range spans {
+			attrs = append(attrs, slog.Duration(sp.Path(), sp.durationLocked(now)))
+			walk(sp.children)
+		}
+	}
+	walk(s.spans)
+	return slog.GroupValue(attrs...)
+}
+
+// WriteSummary writes a table of spans and laps to w, suitable for a
+// --timings flag. Nested spans are indented under their parent, each with
+// its duration and share of the total; open spans are marked "(running)".
+func (s *Stopwatch) WriteSummary(w io.Writer) error {
+	now := s.c.Now()
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	s.mu.Lock()
+	total := now.Sub(s.start)
+	row := func(depth int, name string, d time.Duration, note string) {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\n",
+			strings.Repeat("  ", depth), name, formatSpan(d), percent(d, total), note)
+	}
+	var walk func(spans []*Span, depth int)
+	walk = func(spans []*Span, depth int) {
+		for _, sp := range spans {
+			note := ""
+			if !sp.ended {
+				note = "(running)"
+			}
+			row(depth, sp.name, sp.durationLocked(now), note)
+			walk(sp.children, depth+1)
+		}
+	}
+	walk(s.spans, 0)
+	if len(s.laps) > 0 {
+		fmt.Fprint(tw, "laps\t\t\t\n")
+		for _, lap := range s.laps {
+			row(1, lap.Name, lap.Duration, "")
+		}
+	}
+	row(0, "total", total, "")
+	s.mu.Unlock()
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// Drop the padding tabwriter leaves after the last non-empty cell.
+	for line := range strings.Lines(buf.String()) {
+		if _, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatSpan rounds d to a precision that reads well in a summary.
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
+
+func percent(d, total time.Duration) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(d)*100/float64(total))
+}
+
+var _ slog.LogValuer = (*Stopwatch)(nil)
@@ -0,0 +1,120 @@
+package clock_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/mylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopwatch_Laps(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	sw := clock.NewStopwatch(tc)
+
+	tc.Advance(2 * time.Second)
+	assert.Equal(t, 2*time.Second, sw.Lap("parse"))
+	tc.Advance(500 * time.Millisecond)
+	assert.Equal(t, 500*time.Millisecond, sw.Lap("render"))
+
+	assert.Equal(t, []clock.Lap{
+		{Name: "parse", At: 2 * time.Second, Duration: 2 * time.Second},
+		{Name: "render", At: 2500 * time.Millisecond, Duration: 500 * time.Millisecond},
+	}, sw.Laps())
+	assert.Equal(t, 2500*time.Millisecond, sw.Elapsed())
+}
+
+func TestStopwatch_Spans(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	sw := clock.NewStopwatch(tc)
+
+	load := sw.Start("load")
+	tc.Advance(100 * time.Millisecond)
+	parse := sw.Start("parse")
+	tc.Advance(300 * time.Millisecond)
+	assert.Equal(t, 300*time.Millisecond, parse.End())
+	assert.Equal(t, "load/parse", parse.Path())
+
+	fetch := sw.Start("fetch")
+	tc.Advance(time.Second)
+	assert.Equal(t, 1400*time.Millisecond, load.End(), "ending a parent ends open children")
+	assert.Equal(t, time.Second, fetch.Duration())
+	tc.Advance(time.Second)
+	assert.Equal(t, 1400*time.Millisecond, load.End(), "End is idempotent")
+
+	errBoom := errors.New("boom")
+	err := sw.Time("save", func() error {
+		tc.Advance(200 * time.Millisecond)
+		return errBoom
+	})
+	assert.ErrorIs(t, err, errBoom)
+
+	spans := sw.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "save", spans[1].Name())
+	assert.Equal(t, 200*time.Millisecond, spans[1].Duration())
+	require.Len(t, spans[0].Children(), 2)
+}
+
+func TestStopwatch_WriteSummary(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	sw := clock.NewStopwatch(tc)
+
+	cfg := sw.Start("config")
+	tc.Advance(250 * time.Millisecond)
+	cfg.End()
+	run := sw.Start("run")
+	child := run.Start("fetch")
+	tc.Advance(1500 * time.Millisecond)
+	child.End()
+	tc.Advance(250 * time.Millisecond)
+	sw.Lap("ready")
+
+	var buf bytes.Buffer
+	require.NoError(t, sw.WriteSummary(&buf))
+	assert.Equal(t, ""+
+		"config   250ms  12.5%\n"+
+		"run      1.75s  87.5%   (running)\n"+
+		"  fetch  1.5s   75.0%\n"+
+		"laps\n"+
+		"  ready  2s     100.0%\n"+
+		"total    2s     100.0%\n",
+		buf.String())
+}
+
+func TestStopwatch_Logging(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	lg, th := mylog.NewTestLogger(t, slog.LevelDebug)
+	sw := clock.NewStopwatch(tc, clock.WithSpanLogger(lg))
+
+	outer := sw.Start("build")
+	sw.Start("compile")
+	tc.Advance(3 * time.Second)
+	outer.End()
+
+	entries := mylog.FindEntries(th, func(e mylog.LoggedEntry) bool {
+		return e.Msg == "span finished"
+	})
+	require.Len(t, entries, 2)
+	assert.Equal(t, "build/compile", entries[0].Attrs["span"])
+	assert.Equal(t, "build", entries[1].Attrs["span"])
+	assert.Equal(t, 3*time.Second, entries[1].Attrs["duration"])
+
+	lg.Info("done", "timings", sw)
+	done := mylog.FindEntries(th, func(e mylog.LoggedEntry) bool { return e.Msg == "done" })
+	require.Len(t, done, 1)
+	assert.NotNil(t, done[0].Attrs["timings"])
+}
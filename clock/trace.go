@@ -0,0 +1,183 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TraceOp is the kind of scheduling event recorded in a [TestClock] trace.
+type TraceOp string
+
+const (
+	// TraceRegister records a new timer, After channel or ticker.
+	TraceRegister TraceOp = "register"
+	// TraceFire records a timer firing or a ticker ticking.
+	TraceFire TraceOp = "fire"
+	// TraceStop records a pending timer or running ticker being stopped.
+	TraceStop TraceOp = "stop"
+	// TraceReset records a timer being reset. The event carries the ID of
+	// the replacement entry and, in Prev, the ID it replaces.
+	TraceReset TraceOp = "reset"
+	// TraceDrop records a tick dropped because the ticker channel was full.
+	TraceDrop TraceOp = "drop"
+)
+
+// TraceKind identifies what kind of scheduled entry an event is about.
+type TraceKind string
+
+const (
+	TraceTicker    TraceKind = "ticker"
+	TraceAfterFunc TraceKind = "afterfunc"
+	TraceAfter     TraceKind = "after"
+)
+
+// TraceEvent is one entry in a [TestClock] trace.
+type TraceEvent struct {
+	Op   TraceOp
+	Kind TraceKind
+	// ID identifies the timer or ticker. IDs increase in registration order;
+	// a ticker keeps its ID across ticks, and a reset timer gets a new one.
+	ID uint64
+	// Prev is the ID replaced by a reset, or 0.
+	Prev uint64
+	// At is the clock time of the event. Fires are recorded at their
+	// deadline.
+	At time.Time
+	// Deadline is when the entry is due to fire. It is zero for stops.
+	Deadline time.Time
+}
+
+// String formats e on one line with absolute times.
+func (e TraceEvent) String() string {
+	ts := func(t time.Time) string { return t.Format(time.RFC3339Nano) }
+	return ts(e.At) + "  " + e.describe(ts)
+}
+
+// describe formats e without its time, rendering times with ts.
+func (e TraceEvent) describe(ts func(time.Time) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-8s %-9s #%d", e.Op, e.Kind, e.ID)
+	if e.Prev != 0 {
+		fmt.Fprintf(&b, " (was #%d)", e.Prev)
+	}
+	if (e.Op == TraceRegister || e.Op == TraceReset) && !e.Deadline.IsZero() {
+		fmt.Fprintf(&b, " due %s", ts(e.Deadline))
+	}
+	return b.String()
+}
+
+// WithTrace starts the TestClock with tracing enabled; see
+// [TestClock.StartTrace].
+func WithTrace() TestClockOption {
+	return func(c *TestClock) {
+		c.StartTrace()
+	}
+}
+
+// StartTrace begins recording registrations, fires, stops and resets of the
+// clock's timers, After channels and tickers. Events recorded before an
+// earlier StopTrace are kept.
+func (c *TestClock) StartTrace() {
+	c.sched.mu.Lock()
+	c.sched.tracing = true
+	c.sched.mu.Unlock()
+}
+
+// StopTrace stops recording events.
+func (c *TestClock) StopTrace() {
+	c.sched.mu.Lock()
+	c.sched.tracing = false
+	c.sched.mu.Unlock()
+}
+
+// Trace returns a copy of the events recorded so far, in the order they
+// happened.
+func (c *TestClock) Trace() []TraceEvent {
+	c.sched.mu.Lock()
+	defer c.sched.mu.Unlock()
+	return append([]TraceEvent(nil), c.sched.events...)
+}
+
+// Timeline renders the trace as one event per line, with times shown as
+// offsets from the first event:
+//
+//	+0s  register afterfunc #1 due +5s
+//	+5s  fire     afterfunc #1
+func (c *TestClock) Timeline() string {
+	events := c.Trace()
+	if len(events) == 0 {
+		return "(no clock events)\n"
+	}
+	origin := events[0].At
+	rel := func(t time.Time) string {
+		d := t.Sub(origin)
+		if d < 0 {
+			return d.String()
+		}
+		return "+" + d.String()
+	}
+	width := 0
+	for _, e := range events {
+		width = max(width, len(rel(e.At)))
+	}
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, rel(e.At), e.describe(rel))
+	}
+	return b.String()
+}
+
+// TraceT is the subset of testing.TB used by [TestClock.LogTraceOnFailure].
+type TraceT interface {
+	Helper()
+	Cleanup(func())
+	Failed() bool
+	Logf(format string, args ...any)
+}
+
+// LogTraceOnFailure starts tracing and, if t has failed by the end of the
+// test, logs the timeline so the failure output shows which timers fired in
+// which order.
+func (c *TestClock) LogTraceOnFailure(t TraceT) {
+	t.Helper()
+	c.StartTrace()
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("clock timeline:\n%s", c.Timeline())
+		}
+	})
+}
+
+// traceLocked records op for entry e when tracing. Caller must hold s.mu.
+func (s *scheduler) traceLocked(op TraceOp, e *schedEntry, prev uint64) {
+	if !s.tracing {
+		return
+	}
+	ev := TraceEvent{Op: op, ID: e.id, Prev: prev, At: *s.now, Deadline: e.deadline}
+	switch e.kind {
+	case kindTicker:
+		ev.Kind = TraceTicker
+		ev.ID = e.ticker.id
+	case kindAfterFunc:
+		ev.Kind = TraceAfterFunc
+	case kindAfter:
+		ev.Kind = TraceAfter
+	}
+	switch op {
+	case TraceFire:
+		ev.At = e.deadline
+	case TraceStop:
+		ev.Deadline = time.Time{}
+	}
+	s.recordLocked(ev)
+}
+
+// recordLocked appends ev to the trace. Caller must hold s.mu.
+func (s *scheduler) recordLocked(ev TraceEvent) {
+	ev.At = s.inLocked(ev.At)
+	if !ev.Deadline.IsZero() {
+		ev.Deadline = s.inLocked(ev.Deadline)
+	}
+	s.events = append(s.events, ev)
+}
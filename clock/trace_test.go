@@ -0,0 +1,150 @@
+package clock_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestClock_Trace(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithTrace())
+
+	timer := tc.AfterFunc(5*time.Second, func() {})
+	ticker := tc.NewTicker(2 * time.Second)
+	tc.After(time.Hour)
+	tc.Advance(4 * time.Second)
+	timer.Reset(3 * time.Second)
+	ticker.Stop()
+	timer.Stop()
+
+	var got []string
+	for _, e := range tc.Trace() {
+		got = append(got, fmt.Sprintf("%s %s #%d prev=%d at=%s",
+			e.Op, e.Kind, e.ID, e.Prev, e.At.Sub(seed)))
+	}
+	assert.Equal(t, []string{
+		"register afterfunc #1 prev=0 at=0s",
+		"register ticker #2 prev=0 at=0s",
+		"register after #3 prev=0 at=0s",
+		"fire ticker #2 prev=0 at=2s",
+		"fire ticker #2 prev=0 at=4s",
+		"drop ticker #2 prev=0 at=4s",
+		"reset afterfunc #6 prev=1 at=4s",
+		"stop ticker #2 prev=0 at=4s",
+		"stop afterfunc #6 prev=0 at=4s",
+	}, got)
+
+	trace := tc.Trace()
+	assert.Equal(t, seed.Add(7*time.Second), trace[6].Deadline)
+	assert.True(t, trace[7].Deadline.IsZero())
+}
+
+func TestTestClock_TraceDisabled(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	tc.AfterFunc(time.Second, func() {})
+	assert.Empty(t, tc.Trace())
+
+	tc.StartTrace()
+	tc.Advance(time.Second)
+	tc.StopTrace()
+	tc.AfterFunc(time.Second, func() {})
+	require.Len(t, tc.Trace(), 1)
+	assert.Equal(t, clock.TraceFire, tc.Trace()[0].Op)
+}
+
+func TestTestClock_Timeline(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed)
+	assert.Equal(t, "(no clock events)\n", tc.Timeline())
+
+	tc.StartTrace()
+	tc.AfterFunc(5*time.Second, func() {})
+	tc.After(90 * time.Second)
+	tc.RunUntilIdle()
+
+	assert.Equal(t, ""+
+		"+0s     register afterfunc #1 due +5s\n"+
+		"+0s     register after     #2 due +1m30s\n"+
+		"+5s     fire     afterfunc #1\n"+
+		"+1m30s  fire     after     #2\n",
+		tc.Timeline())
+}
+
+// fakeT records what LogTraceOnFailure does with a test.
+type fakeT struct {
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (f *fakeT) Helper()           {}
+func (f *fakeT) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeT) Failed() bool      { return f.failed }
+func (f *fakeT) Logf(format string, args ...any) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func TestTestClock_LogTraceOnFailure(t *testing.T) {
+	t.Parallel()
+
+	for _, failed := range []bool{false, true} {
+		ft := &fakeT{failed: failed}
+		tc := clock.NewTestClock(seed)
+		tc.LogTraceOnFailure(ft)
+		tc.AfterFunc(time.Second, func() {})
+		for _, fn := range ft.cleanups {
+			fn()
+		}
+		if failed {
+			require.Len(t, ft.logs, 1)
+			assert.Contains(t, ft.logs[0], "register afterfunc #1 due +1s")
+		} else {
+			assert.Empty(t, ft.logs)
+		}
+	}
+}
+
+func TestTestClock_StopAfterFire(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithTrace(), clock.WithSyncCallbacks())
+
+	timer := tc.AfterFunc(time.Second, func() {})
+	tc.Advance(time.Second)
+	tc.Advance(time.Second)
+	assert.False(t, timer.Stop(), "Stop after fire")
+	assert.False(t, timer.Reset(time.Second), "Reset after fire")
+
+	var got []string
+	for _, e := range tc.Trace() {
+		got = append(got, fmt.Sprintf("%s %s #%d at=%s", e.Op, e.Kind, e.ID, e.At.Sub(seed)))
+	}
+	assert.Equal(t, []string{
+		"register afterfunc #1 at=0s",
+		"fire afterfunc #1 at=1s",
+		"reset afterfunc #2 at=2s",
+	}, got)
+}
+
+func TestTestClock_DeadlineCancelAfterFire(t *testing.T) {
+	t.Parallel()
+
+	tc := clock.NewTestClock(seed, clock.WithTrace(), clock.WithSyncCallbacks())
+	ctx, cancel := clock.WithTimeout(t.Context(), tc, time.Second)
+	tc.Advance(time.Second)
+	<-ctx.Done()
+	cancel()
+
+	for _, e := range tc.Trace() {
+		assert.NotEqual(t, clock.TraceStop, e.Op, "cancel after the deadline stops nothing")
+	}
+}
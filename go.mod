@@ -2,12 +2,14 @@ module github.com/jlrickert/cli-toolkit
 
 go 1.25.0
 
-require golang.org/x/term v0.40.0
+require (
+	golang.org/x/term v0.40.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
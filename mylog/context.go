@@ -0,0 +1,75 @@
+package mylog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx carrying attrs in addition to any attributes
+// already attached. Loggers using a [ContextHandler] add them to every record
+// logged with that context, so attributes such as a request or invocation ID
+// follow a call chain without threading a logger through it.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := Attrs(ctx)
+	merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+	merged = append(merged, prev...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// Attrs returns the attributes attached to ctx with [WithAttrs].
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return slices.Clip(attrs)
+}
+
+// ContextHandler is a slog.Handler that adds the attributes attached to the
+// record context with [WithAttrs] before passing the record to the wrapped
+// handler. Use the *Context logging methods, such as InfoContext, so the
+// handler sees the context.
+type ContextHandler struct {
+	inner slog.Handler
+}
+
+// NewContextHandler wraps h in a [ContextHandler].
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	if ch, ok := h.(*ContextHandler); ok {
+		return ch
+	}
+	return &ContextHandler{inner: h}
+}
+
+// Enabled reports whether the wrapped handler handles level.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle adds the context attributes to r and passes it on.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := Attrs(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{inner: h.inner.WithGroup(name)}
+}
+
+var _ slog.Handler = (*ContextHandler)(nil)
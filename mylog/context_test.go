@@ -0,0 +1,39 @@
+package mylog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/mylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttrs_Accumulates(t *testing.T) {
+	t.Parallel()
+
+	ctx := mylog.WithAttrs(context.Background(), slog.String("a", "1"))
+	child := mylog.WithAttrs(ctx, slog.String("b", "2"))
+
+	assert.Equal(t, []slog.Attr{slog.String("a", "1")}, mylog.Attrs(ctx))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("b", "2")}, mylog.Attrs(child))
+	assert.Empty(t, mylog.Attrs(context.Background()))
+}
+
+func TestContextHandler_AddsContextAttrs(t *testing.T) {
+	t.Parallel()
+
+	th := mylog.NewTestHandler(nil)
+	lg := slog.New(mylog.NewContextHandler(th)).With(slog.String("static", "x"))
+
+	ctx := mylog.WithAttrs(context.Background(), slog.String("request", "r-1"))
+	lg.InfoContext(ctx, "with context")
+	lg.Info("without context")
+
+	entries := mylog.FindEntries(th, func(mylog.LoggedEntry) bool { return true })
+	require.Len(t, entries, 2)
+	assert.Equal(t, "r-1", entries[0].Attrs["request"])
+	assert.Equal(t, "x", entries[0].Attrs["static"])
+	assert.NotContains(t, entries[1].Attrs, "request")
+}
@@ -0,0 +1,85 @@
+package sandbox
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/bundle"
+)
+
+// bundleSkipEnv lists variables FromBundle leaves to the sandbox: identity
+// and working directory are restored separately, and TMPDIR must stay inside
+// the jail.
+var bundleSkipEnv = map[string]bool{
+	"HOME":   true,
+	"USER":   true,
+	"PWD":    true,
+	"TMPDIR": true,
+}
+
+// FromBundle creates a sandbox that reproduces the runtime recorded in the
+// debug bundle at the host path bundlePath, typically a file under testdata.
+//
+// The sandbox gets the bundle's home, user, environment, working directory,
+// clock time, process info and terminal size. Redacted variables are left
+// unset. The jail starts empty apart from the working directory; add files
+// with opts, which are applied after the bundle state.
+func FromBundle(t *testing.T, bundlePath string, opts ...Option) *Sandbox {
+	t.Helper()
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		t.Fatalf("FromBundle: %v", err)
+	}
+	defer f.Close()
+	b, err := bundle.Read(f)
+	if err != nil {
+		t.Fatalf("FromBundle: %s: %v", bundlePath, err)
+	}
+	return NewSandboxFromBundle(t, b, opts...)
+}
+
+// NewSandboxFromBundle is like [FromBundle] for an already decoded bundle.
+func NewSandboxFromBundle(t *testing.T, b *bundle.Bundle, opts ...Option) *Sandbox {
+	t.Helper()
+	desc := b.Runtime
+
+	env := map[string]string{}
+	for k, v := range desc.Env {
+		if bundleSkipEnv[k] || v == toolkit.Redacted {
+			continue
+		}
+		env[k] = v
+	}
+
+	bundleOpts := []Option{WithEnvMap(env)}
+	if !desc.Now.IsZero() {
+		bundleOpts = append(bundleOpts, WithClock(desc.Now))
+	}
+	if desc.Location != "" && desc.Location != "Local" {
+		if loc, err := time.LoadLocation(desc.Location); err == nil {
+			bundleOpts = append(bundleOpts, WithLocation(loc))
+		}
+	}
+	if desc.Process != nil {
+		p := *desc.Process
+		bundleOpts = append(bundleOpts, func(f *Sandbox) {
+			f.rt.SetProcessInfo(p)
+		})
+	}
+	if desc.Terminal.IsTTY {
+		bundleOpts = append(bundleOpts, WithTTY(desc.Terminal.Width, desc.Terminal.Height))
+	}
+	if desc.Wd != "" {
+		bundleOpts = append(bundleOpts, func(f *Sandbox) {
+			f.t.Helper()
+			if err := f.Mkdir(desc.Wd, true); err != nil {
+				f.t.Fatalf("FromBundle: mkdir %s failed: %v", desc.Wd, err)
+			}
+		}, WithWd(desc.Wd))
+	}
+
+	options := &Options{Home: desc.Home, User: desc.User}
+	return NewSandbox(t, options, append(bundleOpts, opts...)...)
+}
@@ -0,0 +1,54 @@
+package sandbox_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromBundle_ReproducesRuntime(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	src := tu.NewSandbox(t, &tu.Options{Home: "/home/alice", User: "alice"},
+		tu.WithClock(at),
+		tu.WithEnv("EDITOR", "nano"),
+		tu.WithEnv("GITHUB_TOKEN", "ghp_123"),
+		tu.WithTTY(100, 40),
+	)
+	require.NoError(t, src.Mkdir("/home/alice/project", true))
+	require.NoError(t, src.Setwd("/home/alice/project"))
+	src.Runtime().SetProcessInfo(toolkit.ProcessInfo{Name: "tool", UID: "inv-1"})
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "bundle.tar.gz"))
+	require.NoError(t, err)
+	require.NoError(t, bundle.New(src.Runtime()).Write(f))
+	require.NoError(t, f.Close())
+
+	sb := tu.FromBundle(t, f.Name())
+	rt := sb.Runtime()
+
+	home, err := sb.GetHome()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/alice", home)
+	assert.Equal(t, "alice", rt.Get("USER"))
+	wd, err := sb.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/alice/project", wd)
+	assert.Equal(t, at, sb.Now())
+	assert.Equal(t, "nano", rt.Get("EDITOR"))
+	assert.False(t, rt.Has("GITHUB_TOKEN"))
+	require.NotNil(t, rt.Process())
+	assert.Equal(t, "inv-1", rt.Process().UID)
+	term := rt.Terminal()
+	assert.True(t, term.IsTTY)
+	assert.Equal(t, 100, term.Width)
+	assert.NotEqual(t, src.GetJail(), sb.GetJail())
+}
@@ -0,0 +1,251 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// CommandHandler implements a scripted command. It may read cmd.Stdin and
+// write to cmd.Stdout/cmd.Stderr, and returns the exit code to report.
+type CommandHandler func(ctx context.Context, cmd *toolkit.Command) (int, error)
+
+// CommandCall records a single invocation seen by a FakeCommander.
+type CommandCall struct {
+	Argv []string
+	Dir  string
+	Env  []string
+}
+
+// CommandRule is a scripted response registered with [FakeCommander.On].
+type CommandRule struct {
+	pattern []string
+	stdout  []byte
+	stderr  []byte
+	code    int
+	err     error
+	handler CommandHandler
+}
+
+// Stdout sets the output written to the command's stdout.
+func (r *CommandRule) Stdout(s string) *CommandRule {
+	r.stdout = []byte(s)
+	return r
+}
+
+// Stderr sets the output written to the command's stderr.
+func (r *CommandRule) Stderr(s string) *CommandRule {
+	r.stderr = []byte(s)
+	return r
+}
+
+// Exit sets the exit code reported by the command. Non-zero codes surface as
+// a [*toolkit.ExitError].
+func (r *CommandRule) Exit(code int) *CommandRule {
+	r.code = code
+	return r
+}
+
+// Fail makes the command fail to start with err, for example exec.ErrNotFound
+// or a permission error.
+func (r *CommandRule) Fail(err error) *CommandRule {
+	r.err = err
+	return r
+}
+
+// Do runs fn instead of emitting canned output. Canned stdout and stderr are
+// written before fn is called; the code fn returns replaces any Exit code.
+func (r *CommandRule) Do(fn CommandHandler) *CommandRule {
+	r.handler = fn
+	return r
+}
+
+// FakeCommander is a scripted [toolkit.Commander] for tests. Rules are matched
+// in registration order against the full argv (program name followed by
+// arguments). In each pattern element "*" matches any run of characters
+// (including "/") and "?" matches one character; the program name also
+// matches against its base name, and a trailing "..." element matches any
+// remaining arguments.
+//
+// Commands that match no rule fail with an error wrapping exec.ErrNotFound,
+// which keeps sandboxed tests from reaching the host.
+//
+// FakeCommander is safe for concurrent use.
+type FakeCommander struct {
+	mu    sync.Mutex
+	rules []*CommandRule
+	calls []CommandCall
+}
+
+// NewFakeCommander returns an empty FakeCommander.
+func NewFakeCommander() *FakeCommander {
+	return &FakeCommander{}
+}
+
+// On registers a rule for commands matching pattern and returns it for
+// configuration.
+func (f *FakeCommander) On(pattern ...string) *CommandRule {
+	r := &CommandRule{pattern: pattern}
+	f.mu.Lock()
+	f.rules = append(f.rules, r)
+	f.mu.Unlock()
+	return r
+}
+
+// Calls returns a copy of all recorded invocations in order.
+func (f *FakeCommander) Calls() []CommandCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Reset removes all rules and recorded calls.
+func (f *FakeCommander) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = nil
+	f.calls = nil
+}
+
+// Run implements [toolkit.Commander].
+func (f *FakeCommander) Run(ctx context.Context, cmd *toolkit.Command) error {
+	argv := cmd.Argv()
+
+	f.mu.Lock()
+	f.calls = append(f.calls, CommandCall{
+		Argv: argv,
+		Dir:  cmd.Dir,
+		Env:  slices.Clone(cmd.Env),
+	})
+	var rule *CommandRule
+	for _, r := range f.rules {
+		if matchArgv(r.pattern, argv) {
+			rule = r
+			break
+		}
+	}
+	f.mu.Unlock()
+
+	if rule == nil {
+		return fmt.Errorf("sandbox: no scripted command matches %q: %w", argv, exec.ErrNotFound)
+	}
+	if rule.err != nil {
+		return fmt.Errorf("sandbox: start %q: %w", cmd.Name, rule.err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(rule.stdout) > 0 && cmd.Stdout != nil {
+		if _, err := cmd.Stdout.Write(rule.stdout); err != nil {
+			return err
+		}
+	}
+	if len(rule.stderr) > 0 && cmd.Stderr != nil {
+		if _, err := cmd.Stderr.Write(rule.stderr); err != nil {
+			return err
+		}
+	}
+
+	code := rule.code
+	if rule.handler != nil {
+		c, err := rule.handler(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		code = c
+	}
+	if code != 0 {
+		return &toolkit.ExitError{Code: code, Stderr: rule.stderr}
+	}
+	return nil
+}
+
+// EditorHandler returns a CommandHandler that behaves like an editor: it
+// passes the content of the file named by the last argument to fn and writes
+// the result back. The argument is a host path, as passed by [toolkit.Edit];
+// it is mapped back under rt's jail and read and written through rt, so the
+// handler sees the same filesystem as the code under test. Use it with
+// [WithEditor] or a rule matching $EDITOR.
+func EditorHandler(rt *toolkit.Runtime, fn func(content []byte) ([]byte, error)) CommandHandler {
+	return func(ctx context.Context, cmd *toolkit.Command) (int, error) {
+		if len(cmd.Args) == 0 {
+			return 1, fmt.Errorf("sandbox: editor %q: no file argument", cmd.Name)
+		}
+		path := cmd.Args[len(cmd.Args)-1]
+		if j := rt.GetJail(); j != "" && toolkit.IsInJail(j, path) {
+			path = toolkit.RemoveJailPrefix(j, path)
+		}
+		info, err := rt.Stat(path, true)
+		if err != nil {
+			return 1, fmt.Errorf("sandbox: editor: %w", err)
+		}
+		data, err := rt.ReadFile(path)
+		if err != nil {
+			return 1, fmt.Errorf("sandbox: editor: %w", err)
+		}
+		out, err := fn(data)
+		if err != nil {
+			return 1, err
+		}
+		if err := rt.WriteFile(path, out, info.Mode().Perm()); err != nil {
+			return 1, fmt.Errorf("sandbox: editor: %w", err)
+		}
+		return 0, nil
+	}
+}
+
+func matchArgv(pattern, argv []string) bool {
+	if n := len(pattern); n > 0 && pattern[n-1] == "..." {
+		if len(argv) < n-1 {
+			return false
+		}
+		return matchArgv(pattern[:n-1], argv[:n-1])
+	}
+	if len(pattern) != len(argv) {
+		return false
+	}
+	for i, p := range pattern {
+		if globMatch(p, argv[i]) {
+			continue
+		}
+		if i == 0 && globMatch(p, filepath.Base(argv[i])) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// globMatch reports whether s matches pattern, where "*" matches any run of
+// characters and "?" matches exactly one.
+func globMatch(pattern, s string) bool {
+	px, sx := 0, 0
+	starPx, starSx := -1, 0
+	for sx < len(s) {
+		switch {
+		case px < len(pattern) && (pattern[px] == '?' || pattern[px] == s[sx]):
+			px++
+			sx++
+		case px < len(pattern) && pattern[px] == '*':
+			starPx, starSx = px, sx
+			px++
+		case starPx >= 0:
+			starSx++
+			px, sx = starPx+1, starSx
+		default:
+			return false
+		}
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
+
+var _ toolkit.Commander = (*FakeCommander)(nil)
@@ -0,0 +1,135 @@
+package sandbox_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeCommander_CannedOutput(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	sb.Commands().On("git", "rev-parse", "...").Stdout("abc123\n")
+
+	out, err := sb.Runtime().Command(sb.Context(), "git", "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "abc123\n", string(out))
+
+	calls := sb.Commands().Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"git", "rev-parse", "HEAD"}, calls[0].Argv)
+
+	wd, err := sb.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sb.GetJail(), wd), calls[0].Dir)
+}
+
+func TestFakeCommander_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	sb.Commands().On("/usr/bin/gi?", "status").Stderr("fatal: not a git repository\n").Exit(128)
+
+	_, err := sb.Runtime().Command(sb.Context(), "/usr/bin/git", "status").Output()
+	var exitErr *toolkit.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 128, exitErr.Code)
+	assert.Equal(t, "fatal: not a git repository\n", string(exitErr.Stderr))
+}
+
+func TestFakeCommander_FirstMatchWinsAndBaseName(t *testing.T) {
+	t.Parallel()
+
+	fc := tu.NewFakeCommander()
+	fc.On("tool", "a").Stdout("first")
+	fc.On("tool", "*").Stdout("second")
+
+	rt := newProcessRuntime(t)
+	require.NoError(t, rt.SetCommander(fc))
+
+	out, err := rt.Command(t.Context(), "/opt/bin/tool", "a").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(out))
+
+	out, err = rt.Command(t.Context(), "tool", "b").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(out))
+}
+
+func TestFakeCommander_UnmatchedIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	err := sb.Runtime().Command(sb.Context(), "rm", "-rf", "/").Run()
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	sb.Commands().On("vim", "...").Fail(exec.ErrNotFound)
+	err = sb.Runtime().Command(sb.Context(), "vim").Run()
+	require.ErrorIs(t, err, exec.ErrNotFound)
+}
+
+func TestFakeCommander_HandlerUsesStreams(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	sb.Commands().On("tr", "a-z", "A-Z").Do(func(ctx context.Context, cmd *toolkit.Command) (int, error) {
+		b, err := io.ReadAll(cmd.Stdin)
+		if err != nil {
+			return 1, err
+		}
+		_, err = io.WriteString(cmd.Stdout, strings.ToUpper(string(b)))
+		return 0, err
+	})
+
+	c := sb.Runtime().Command(sb.Context(), "tr", "a-z", "A-Z")
+	c.Stdin = strings.NewReader("hello")
+	out, err := c.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", string(out))
+}
+
+func TestFakeCommander_ProcessUsesRuntimeStream(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	sb.Commands().On("echo", "...").Do(func(ctx context.Context, cmd *toolkit.Command) (int, error) {
+		_, err := io.WriteString(cmd.Stdout, strings.Join(cmd.Args, " ")+"\n")
+		return 0, err
+	})
+
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		return 0, rt.Command(ctx, "echo", "hi", "there").Run()
+	}, false)
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.NoError(t, res.Err)
+	assert.Equal(t, "hi there\n", string(res.Stdout))
+}
+
+func TestEditorHandler_EditsThroughRuntime(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	rt := sb.Runtime()
+	require.NoError(t, rt.WriteFile("notes.txt", []byte("draft"), 0o644))
+	sb.Commands().On("ed", "...").Do(tu.EditorHandler(rt, func(b []byte) ([]byte, error) {
+		return append(b, " done"...), nil
+	}))
+
+	host, err := rt.HostPath("notes.txt")
+	require.NoError(t, err)
+	require.NoError(t, rt.Command(sb.Context(), "ed", host).Run())
+	require.NoError(t, rt.Command(sb.Context(), "ed", "notes.txt").Run())
+
+	got, err := rt.ReadFile("notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "draft done done", string(got))
+}
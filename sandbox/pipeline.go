@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jlrickert/cli-toolkit/clock"
 	"github.com/jlrickert/cli-toolkit/toolkit"
 )
 
@@ -159,9 +160,11 @@ func (p *Pipeline) Run(ctx context.Context, rt *toolkit.Runtime) *PipelineResult
 	return result
 }
 
-// RunWithTimeout executes the pipeline with a deadline.
+// RunWithTimeout executes the pipeline with a deadline measured by the
+// runtime clock. With a sandbox TestClock the deadline passes only when the
+// clock is advanced.
 func (p *Pipeline) RunWithTimeout(ctx context.Context, rt *toolkit.Runtime, timeout time.Duration) *PipelineResult {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel := clock.WithTimeout(ctx, rt.SchedulingClock(), timeout)
 	defer cancel()
 	return p.Run(ctx, rt)
 }
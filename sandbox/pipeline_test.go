@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tu "github.com/jlrickert/cli-toolkit/sandbox"
 	"github.com/jlrickert/cli-toolkit/toolkit"
@@ -80,3 +81,30 @@ func TestPipeline_TwoStages(t *testing.T) {
 	assert.Equal(t, "C:ALPHA\nC:BETA\nC:GAMMA\n", string(result.Stdout))
 	assert.Equal(t, outBuf.String(), string(result.Stdout))
 }
+
+func TestPipeline_RunWithTimeoutUsesRuntimeClock(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	started := make(chan struct{})
+	runner := func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 1, ctx.Err()
+	}
+	pipeline := tu.NewPipeline(tu.Stage("wait", runner))
+
+	done := make(chan *tu.PipelineResult, 1)
+	go func() { done <- pipeline.RunWithTimeout(t.Context(), sb.Runtime(), 5*time.Second) }()
+
+	<-started
+	select {
+	case <-done:
+		t.Fatal("pipeline finished before the clock advanced")
+	default:
+	}
+
+	sb.Advance(5 * time.Second)
+	result := <-done
+	assert.ErrorIs(t, result.Err, context.DeadlineExceeded)
+}
@@ -3,17 +3,31 @@ package sandbox
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"runtime/debug"
 	"sync"
 	"time"
 
 	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/errs"
 )
 
 // Runner executes a unit of work using explicit runtime dependencies.
 type Runner func(ctx context.Context, rt *toolkit.Runtime) (int, error)
 
+// ErrorRunner adapts a command that returns only an error to a [Runner]. The
+// error is reported with errs.Render, as a command's main function would, and
+// the exit code comes from its classification.
+func ErrorRunner(fn func(ctx context.Context, rt *toolkit.Runtime) error, opts ...errs.RenderOption) Runner {
+	return func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		err := fn(ctx, rt)
+		return errs.Render(rt, err, opts...), err
+	}
+}
+
 // ProcessResult holds the outcome of process execution.
 type ProcessResult struct {
 	Err      error
@@ -44,9 +58,31 @@ type Process struct {
 	outBuf *bytes.Buffer
 	errBuf *bytes.Buffer
 
+	signals *FakeSignals
+
 	mu sync.Mutex
 }
 
+// ErrForcedExit is reported in [ProcessResult.Err] when the runner forces
+// exit through its SignalSource, for example on a second interrupt.
+var ErrForcedExit = errors.New("sandbox: process forced exit")
+
+// ErrRunnerPanic is reported in [ProcessResult.Err] when the runner panics.
+// The error message includes the panic value and the runner's stack.
+var ErrRunnerPanic = errors.New("sandbox: runner panicked")
+
+// ErrRunnerExited is reported in [ProcessResult.Err] when the runner ends
+// without returning, for example through t.FailNow or runtime.Goexit.
+var ErrRunnerExited = errors.New("sandbox: runner exited without returning")
+
+// ErrRunnerLeaked is joined into [ProcessResult.Err] when the runner is still
+// running after a forced exit and the context cancellation that follows.
+var ErrRunnerLeaked = errors.New("sandbox: runner still running after forced exit")
+
+// forcedExitGrace bounds how long Run waits for a runner to return after a
+// forced exit. It is real time, since a test clock may never advance.
+const forcedExitGrace = time.Second
+
 // NewProcess constructs a Process bound to a Runner function.
 func NewProcess(fn Runner, isTTY bool) *Process {
 	return &Process{runner: fn, isTTY: isTTY}
@@ -134,6 +170,23 @@ func (p *Process) SetArgs(args []string) {
 	p.args = args
 }
 
+// Signal delivers sig to the running runner through its fake
+// [toolkit.SignalSource]. Signals sent before the runner subscribes are held
+// until it does. Each Run gets a fresh source, so a signal sent after a run
+// ends is held for the next one.
+func (p *Process) Signal(sig os.Signal) {
+	p.signalSource().Send(sig)
+}
+
+func (p *Process) signalSource() *FakeSignals {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.signals == nil {
+		p.signals = NewFakeSignals()
+	}
+	return p.signals
+}
+
 // Write writes data to the process stdin.
 func (p *Process) Write(b []byte) (int, error) {
 	p.mu.Lock()
@@ -156,7 +209,15 @@ func (p *Process) Close() error {
 	return nil
 }
 
-// Run executes the process runner synchronously using a cloned runtime.
+// Run executes the process runner synchronously using a cloned runtime. The
+// clone is closed when the runner returns, running hooks it registered with
+// OnClose; close errors are joined into the result error.
+//
+// When the runner forces exit through its SignalSource, Run cancels the
+// runner's context and waits up to one second of real time for it to return
+// so its writes do not race the result. A runner that ignores cancellation
+// past that is reported with [ErrRunnerLeaked] and keeps running in the
+// background.
 func (p *Process) Run(ctx context.Context, rt *toolkit.Runtime) *ProcessResult {
 	result := &ProcessResult{}
 
@@ -223,13 +284,83 @@ func (p *Process) Run(ctx context.Context, rt *toolkit.Runtime) *ProcessResult {
 		IsPiped: isPiped,
 		IsTTY:   p.isTTY,
 	}
+	if parent := rt.Stream(); parent != nil {
+		// Keep terminal overrides such as those from WithTTY and WithColor.
+		stream.Width, stream.Height = parent.Width, parent.Height
+		stream.ForceColor, stream.Color = parent.ForceColor, parent.Color
+	}
 	if err := procRt.SetStream(stream); err != nil {
 		result.Err = err
 		result.ExitCode = 1
 		return result
 	}
 
-	exitCode, err := p.runner(ctx, procRt)
+	signals := p.signalSource()
+	if err := procRt.SetSignalSource(signals); err != nil {
+		result.Err = err
+		result.ExitCode = 1
+		return result
+	}
+
+	// Run the runner on its own goroutine so a forced exit can end Run the
+	// way os.Exit would, without waiting for the runner to return. A panic or
+	// Goexit in the runner is reported as a result instead of crashing the
+	// test binary or leaving Run waiting forever.
+	type runResult struct {
+		code int
+		err  error
+	}
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	done := make(chan runResult, 1)
+	go func() {
+		r := runResult{code: 1, err: ErrRunnerExited}
+		defer func() {
+			if v := recover(); v != nil {
+				r = runResult{code: 2, err: fmt.Errorf("%w: %v\n%s", ErrRunnerPanic, v, debug.Stack())}
+			}
+			done <- r
+		}()
+		r.code, r.err = p.runner(runCtx, procRt)
+	}()
+
+	var exitCode int
+	var err error
+	returned := false
+	select {
+	case r := <-done:
+		exitCode, err = r.code, r.err
+		returned = true
+	case <-signals.Exited():
+	}
+	// A runner that calls Exit itself also ends through Goexit, so the
+	// forced exit takes precedence over whatever done reported.
+	if code, exited := signals.ExitCode(); exited {
+		exitCode = code
+		err = fmt.Errorf("%w with code %d", ErrForcedExit, exitCode)
+		if !returned {
+			cancelRun()
+			select {
+			case <-done:
+			case <-time.After(forcedExitGrace):
+				err = errors.Join(err, ErrRunnerLeaked)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	if p.signals == signals {
+		// Later signals belong to the next run.
+		p.signals = nil
+	}
+	p.mu.Unlock()
+
+	if !errors.Is(err, ErrForcedExit) {
+		// Release resources the runner registered, as process exit would.
+		if closeErr := procRt.Close(context.WithoutCancel(ctx)); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}
 
 	p.mu.Lock()
 	if p.stdoutW != nil {
@@ -3,15 +3,20 @@ package sandbox_test
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"runtime"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	tu "github.com/jlrickert/cli-toolkit/sandbox"
 	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/errs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -240,3 +245,108 @@ func TestProcess_RunWithIO(t *testing.T) {
 	assert.Equal(t, expected, out.String())
 	assert.Equal(t, expected, string(result.Stdout))
 }
+
+func TestProcess_SignalCancelsRunner(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		ctx, stop := rt.Signals().NotifyContext(ctx)
+		defer stop()
+		<-ctx.Done()
+		var sigErr *toolkit.SignalError
+		if errors.As(context.Cause(ctx), &sigErr) {
+			return sigErr.ExitCode(), nil
+		}
+		return 1, context.Cause(ctx)
+	}, false)
+
+	go proc.Signal(syscall.SIGTERM)
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.NoError(t, res.Err)
+	assert.Equal(t, 143, res.ExitCode)
+}
+
+func TestProcess_ErrorRunner(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	runner := tu.ErrorRunner(func(ctx context.Context, rt *toolkit.Runtime) error {
+		_, err := rt.ReadFile("missing.txt")
+		return errs.WithHint(err, "Create it first.")
+	})
+
+	proc := tu.NewProcess(runner, false)
+	result := proc.Run(sb.Context(), sb.Runtime())
+
+	require.Error(t, result.Err)
+	assert.Equal(t, errs.ExitNotFound, result.ExitCode)
+	assert.Contains(t, string(result.Stderr), "error: ")
+	assert.Contains(t, string(result.Stderr), "hint: Create it first.\n")
+}
+
+func TestProcess_RunnerPanic(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		panic("boom")
+	}, false)
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, tu.ErrRunnerPanic)
+	assert.ErrorContains(t, res.Err, "boom")
+	assert.Equal(t, 2, res.ExitCode)
+}
+
+func TestProcess_RunnerGoexit(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		runtime.Goexit()
+		return 0, nil
+	}, false)
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, tu.ErrRunnerExited)
+	assert.Equal(t, 1, res.ExitCode)
+}
+
+func TestProcess_ReuseAfterForcedExit(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	release := make(chan struct{})
+	defer close(release)
+
+	runs := 0
+	var runnerErr error
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		runs++
+		if runs > 1 {
+			return 0, nil
+		}
+		_, stop := rt.Signals().NotifyContext(ctx)
+		defer stop()
+		// Ignore the interrupts; Run cancels ctx after the forced exit.
+		select {
+		case <-release:
+		case <-ctx.Done():
+			runnerErr = ctx.Err()
+		}
+		return 0, nil
+	}, false)
+
+	proc.Signal(os.Interrupt)
+	proc.Signal(os.Interrupt)
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, tu.ErrForcedExit)
+	assert.NotErrorIs(t, res.Err, tu.ErrRunnerLeaked)
+	assert.Equal(t, 130, res.ExitCode)
+	assert.ErrorIs(t, runnerErr, context.Canceled, "runner returned before Run")
+
+	res = proc.RunWithIO(sb.Context(), sb.Runtime(), strings.NewReader(""))
+	require.NoError(t, res.Err)
+	assert.Equal(t, 0, res.ExitCode)
+}
@@ -1,12 +1,15 @@
 package sandbox
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"fmt"
 	iofs "io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -16,6 +19,10 @@ import (
 	"github.com/jlrickert/cli-toolkit/toolkit"
 )
 
+// DefaultBinDir is the virtual directory [WithExecutable] installs fake
+// executables into.
+const DefaultBinDir = "/usr/local/bin"
+
 // Option is a function used to modify a Sandbox during construction.
 type Option func(f *Sandbox)
 
@@ -52,7 +59,15 @@ func NewSandbox(t *testing.T, options *Options, opts ...Option) *Sandbox {
 	lg, _ := mylog.NewTestLogger(t, mylog.ParseLevel("debug"))
 	clk := clock.NewTestClock(time.Date(2025, 10, 15, 12, 30, 0, 0, time.UTC))
 	hasher := &toolkit.MD5Hasher{}
-	stream := toolkit.DefaultStream()
+	// Buffered streams keep sandbox output off the host terminal, so tests
+	// see the same non-TTY stream however go test is run.
+	stream := &toolkit.Stream{
+		In:  strings.NewReader(""),
+		Out: &bytes.Buffer{},
+		Err: &bytes.Buffer{},
+	}
+	cmds := NewFakeCommander()
+	sigs := NewFakeSignals()
 
 	rt, err := toolkit.NewTestRuntime(
 		jail,
@@ -62,6 +77,8 @@ func NewSandbox(t *testing.T, options *Options, opts ...Option) *Sandbox {
 		toolkit.WithRuntimeLogger(lg),
 		toolkit.WithRuntimeStream(stream),
 		toolkit.WithRuntimeHasher(hasher),
+		toolkit.WithRuntimeCommander(cmds),
+		toolkit.WithRuntimeSignalSource(sigs),
 	)
 	if err != nil {
 		t.Fatalf("NewSandbox: runtime init failed: %v", err)
@@ -117,6 +134,122 @@ func WithClock(t0 time.Time) Option {
 	}
 }
 
+// WithClockTrace returns an Option that records the test clock's timer
+// events and logs them as a timeline if the test fails.
+func WithClockTrace() Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		f.testClock().LogTraceOnFailure(f.t)
+	}
+}
+
+// WithLocation returns an Option that sets the runtime time zone, and the
+// test clock's, to loc. Sandboxes use UTC by default.
+func WithLocation(loc *time.Location) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		if err := f.rt.SetLocation(loc); err != nil {
+			f.t.Fatalf("WithLocation: %v", err)
+		}
+	}
+}
+
+// WithCommander returns an Option that replaces the sandbox's scripted
+// [FakeCommander], for example with toolkit.OsCommander to run real programs.
+func WithCommander(c toolkit.Commander) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		if err := f.rt.SetCommander(c); err != nil {
+			f.t.Fatalf("WithCommander failed: %v", err)
+		}
+	}
+}
+
+// WithExecutable returns an Option that installs a fake executable named name
+// into [DefaultBinDir] and puts that directory on the sandbox PATH.
+func WithExecutable(name string, content []byte) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		f.MustInstallExecutable(DefaultBinDir, name, content)
+	}
+}
+
+// WithTTY returns an Option that makes the sandbox stream report a terminal
+// of the given size. The size is set on the stream, so it takes priority over
+// the host terminal, and is also exported as COLUMNS and LINES for child
+// commands. Processes created with NewProcess keep their own isTTY flag but
+// inherit the size.
+func WithTTY(width, height int) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		stream := *f.rt.Stream()
+		stream.IsTTY = true
+		stream.Width, stream.Height = width, height
+		if err := f.rt.SetStream(&stream); err != nil {
+			f.t.Fatalf("WithTTY failed to set stream: %v", err)
+		}
+		env := f.runtimeEnv()
+		for k, v := range map[string]string{
+			"COLUMNS": strconv.Itoa(width),
+			"LINES":   strconv.Itoa(height),
+		} {
+			if err := env.Set(k, v); err != nil {
+				f.t.Fatalf("WithTTY failed to set %s: %v", k, err)
+			}
+		}
+	}
+}
+
+// WithColor returns an Option that makes terminal detection report level,
+// with or without [WithTTY], regardless of the host terminal and env. TERM
+// and COLORTERM are set to match for child commands.
+func WithColor(level toolkit.ColorLevel) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		stream := *f.rt.Stream()
+		stream.ForceColor, stream.Color = true, level
+		if err := f.rt.SetStream(&stream); err != nil {
+			f.t.Fatalf("WithColor failed to set stream: %v", err)
+		}
+		term, colorterm := "xterm", ""
+		switch level {
+		case toolkit.ColorNone:
+			term = "dumb"
+		case toolkit.Color256:
+			term = "xterm-256color"
+		case toolkit.ColorTrueColor:
+			term, colorterm = "xterm-256color", "truecolor"
+		}
+		env := f.runtimeEnv()
+		if err := env.Set("TERM", term); err != nil {
+			f.t.Fatalf("WithColor failed to set TERM: %v", err)
+		}
+		if colorterm == "" {
+			env.Unset("COLORTERM")
+		} else if err := env.Set("COLORTERM", colorterm); err != nil {
+			f.t.Fatalf("WithColor failed to set COLORTERM: %v", err)
+		}
+	}
+}
+
+// FakeEditor is the $EDITOR value installed by [WithEditor].
+const FakeEditor = "sandbox-editor"
+
+// WithEditor returns an Option that points $EDITOR at a scripted editor which
+// rewrites the edited file with fn. $VISUAL is cleared so it cannot take
+// precedence.
+func WithEditor(fn func(content []byte) ([]byte, error)) Option {
+	return func(f *Sandbox) {
+		f.t.Helper()
+		env := f.runtimeEnv()
+		env.Unset("VISUAL")
+		if err := env.Set("EDITOR", FakeEditor); err != nil {
+			f.t.Fatalf("WithEditor failed to set EDITOR: %v", err)
+		}
+		f.Commands().On(FakeEditor, "...").Do(EditorHandler(f.rt, fn))
+	}
+}
+
 // WithEnvMap returns an Option that seeds multiple environment variables.
 func WithEnvMap(m map[string]string) Option {
 	return func(f *Sandbox) {
@@ -167,6 +300,29 @@ func (sandbox *Sandbox) Runtime() *toolkit.Runtime {
 	return sandbox.rt
 }
 
+// Commands returns the sandbox's scripted [FakeCommander]. External commands
+// run through the runtime fail unless a matching rule is registered. It fails
+// the test if the commander was replaced via [WithCommander].
+func (sandbox *Sandbox) Commands() *FakeCommander {
+	sandbox.t.Helper()
+	fc, ok := sandbox.rt.Commander().(*FakeCommander)
+	if !ok {
+		sandbox.t.Fatalf("sandbox commander is not a *FakeCommander")
+	}
+	return fc
+}
+
+// Signals returns the sandbox's [FakeSignals]. Processes started with
+// [Process.Run] get their own source; use [Process.Signal] to reach them.
+func (sandbox *Sandbox) Signals() *FakeSignals {
+	sandbox.t.Helper()
+	fs, ok := sandbox.rt.SignalSource().(*FakeSignals)
+	if !ok {
+		sandbox.t.Fatalf("sandbox signal source is not a *FakeSignals")
+	}
+	return fs
+}
+
 // AbsPath returns a runtime absolute path.
 func (sandbox *Sandbox) AbsPath(rel string) (string, error) {
 	sandbox.t.Helper()
@@ -211,6 +367,45 @@ func (sandbox *Sandbox) MustWriteFile(path string, data []byte, perm os.FileMode
 	}
 }
 
+// InstallExecutable writes content to dir/name inside the jail with mode
+// 0o755 and prepends dir to the runtime PATH if it is not already listed. It
+// returns the runtime path of the installed file, which is what
+// [toolkit.Runtime.LookPath] reports for name.
+func (sandbox *Sandbox) InstallExecutable(dir, name string, content []byte) (string, error) {
+	sandbox.t.Helper()
+	dirPath, err := sandbox.rt.ResolvePath(dir, false)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dirPath, name)
+	if err := sandbox.rt.WriteFile(path, content, 0o755); err != nil {
+		return "", err
+	}
+	if err := sandbox.rt.Chmod(path, 0o755); err != nil {
+		return "", err
+	}
+
+	entries := filepath.SplitList(sandbox.rt.Get("PATH"))
+	if !slices.Contains(entries, dirPath) {
+		entries = append([]string{dirPath}, entries...)
+		value := strings.Join(entries, string(os.PathListSeparator))
+		if err := sandbox.rt.Set("PATH", value); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// MustInstallExecutable is like InstallExecutable but fails the test on error.
+func (sandbox *Sandbox) MustInstallExecutable(dir, name string, content []byte) string {
+	sandbox.t.Helper()
+	path, err := sandbox.InstallExecutable(dir, name, content)
+	if err != nil {
+		sandbox.t.Fatalf("MustInstallExecutable %s failed: %v", name, err)
+	}
+	return path
+}
+
 func (sandbox *Sandbox) Mkdir(rel string, all bool) error {
 	sandbox.t.Helper()
 	return sandbox.rt.Mkdir(rel, 0o755, all)
@@ -222,7 +417,13 @@ func (sandbox *Sandbox) ResolvePath(rel string) (string, error) {
 	return sandbox.rt.ResolvePath(rel, false)
 }
 
-func (sandbox *Sandbox) cleanup() {}
+// cleanup closes the sandbox runtime, running hooks registered with
+// OnClose on it and any of its clones.
+func (sandbox *Sandbox) cleanup() {
+	if err := sandbox.rt.Close(context.Background()); err != nil {
+		sandbox.t.Errorf("sandbox: close runtime: %v", err)
+	}
+}
 
 func (sandbox *Sandbox) runtimeEnv() toolkit.Env {
 	sandbox.t.Helper()
@@ -1,10 +1,18 @@
 package sandbox_test
 
 import (
+	"context"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/jlrickert/cli-toolkit/clock"
 	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,6 +73,35 @@ func TestSandbox_RuntimeCarriesClock(t *testing.T) {
 	require.False(t, now.IsZero())
 }
 
+func TestSandbox_Location(t *testing.T) {
+	t.Parallel()
+
+	sandbox := tu.NewSandbox(t, nil, tu.WithClock(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.Equal(t, "2025-01-02T03:04:05Z", sandbox.Runtime().Now().Format(time.RFC3339))
+	assert.Equal(t, time.UTC, sandbox.Now().Location())
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	sandbox = tu.NewSandbox(t, nil,
+		tu.WithClock(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		tu.WithLocation(paris))
+	assert.Equal(t, "2025-01-02T04:04:05+01:00", sandbox.Runtime().Now().Format(time.RFC3339))
+	assert.Equal(t, "2025-01-02T04:04:05+01:00", sandbox.Now().Format(time.RFC3339))
+}
+
+func TestSandbox_WithClockTrace(t *testing.T) {
+	t.Parallel()
+
+	sandbox := tu.NewSandbox(t, nil, tu.WithClockTrace())
+	tc := sandbox.Runtime().Clock().(*clock.TestClock)
+	tc.AfterFunc(time.Minute, func() {})
+	sandbox.Advance(time.Minute)
+
+	trace := tc.Trace()
+	require.Len(t, trace, 2)
+	assert.Equal(t, clock.TraceFire, trace[1].Op)
+}
+
 func TestSandbox_RuntimeCarriesEnv(t *testing.T) {
 	t.Parallel()
 
@@ -157,3 +194,76 @@ func TestSandbox_ResolvePath(t *testing.T) {
 		})
 	}
 }
+
+// TestSandbox_InstallExecutable verifies fake executables are installed in
+// the jail and resolved through the runtime PATH.
+func TestSandbox_InstallExecutable(t *testing.T) {
+	t.Parallel()
+
+	sandbox := tu.NewSandbox(t, nil,
+		tu.WithExecutable("git", []byte("#!/bin/sh\n")),
+	)
+
+	path, err := sandbox.Runtime().LookPath("git")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tu.DefaultBinDir, "git"), path)
+
+	override := sandbox.MustInstallExecutable("~/bin", "git", []byte("#!/bin/sh\n"))
+	path, err = sandbox.Runtime().LookPath("git")
+	require.NoError(t, err)
+	assert.Equal(t, override, path, "later installs take precedence")
+
+	_, err = sandbox.Runtime().LookPath("hg")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+}
+
+func TestSandbox_WithTTYAndColor(t *testing.T) {
+	t.Parallel()
+
+	// The sandbox stream is never the host terminal, even under a TTY.
+	plain := tu.NewSandbox(t, nil)
+	plainTerm := plain.Runtime().Terminal()
+	assert.False(t, plainTerm.IsTTY)
+	assert.Equal(t, toolkit.ColorNone, plainTerm.Color)
+	_, isFile := plain.Runtime().Stream().Out.(*os.File)
+	assert.False(t, isFile)
+
+	sb := tu.NewSandbox(t, nil, tu.WithTTY(100, 30), tu.WithColor(toolkit.ColorTrueColor))
+	term := sb.Runtime().Terminal()
+	assert.True(t, term.IsTTY)
+	assert.Equal(t, 100, term.Width)
+	assert.Equal(t, 30, term.Height)
+	assert.Equal(t, toolkit.ColorTrueColor, term.Color)
+
+	dumb := tu.NewSandbox(t, nil, tu.WithTTY(80, 24), tu.WithColor(toolkit.ColorNone))
+	assert.Equal(t, toolkit.ColorNone, dumb.Runtime().Terminal().Color)
+
+	// An explicit color level wins over NO_COLOR and a missing TTY.
+	forced := tu.NewSandbox(t, nil, tu.WithEnv("NO_COLOR", "1"), tu.WithColor(toolkit.Color256))
+	assert.Equal(t, toolkit.Color256, forced.Runtime().Terminal().Color)
+}
+
+func TestSandbox_CleanupClosesRuntime(t *testing.T) {
+	t.Parallel()
+
+	var closed []string
+	t.Run("inner", func(t *testing.T) {
+		sb := tu.NewSandbox(t, nil)
+		sb.Runtime().OnClose(func(ctx context.Context) error {
+			closed = append(closed, "sandbox")
+			return nil
+		})
+
+		proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+			rt.OnClose(func(ctx context.Context) error {
+				closed = append(closed, "process")
+				return nil
+			})
+			return 0, nil
+		}, false)
+		res := proc.Run(sb.Context(), sb.Runtime())
+		require.NoError(t, res.Err)
+		assert.Equal(t, []string{"process"}, closed, "process runtime closes when the runner returns")
+	})
+	assert.Equal(t, []string{"process", "sandbox"}, closed)
+}
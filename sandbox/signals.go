@@ -0,0 +1,116 @@
+package sandbox
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// FakeSignals is a [toolkit.SignalSource] driven by tests. Signals are
+// delivered with [FakeSignals.Send]; a signal sent before any subscriber asks
+// for it is held and delivered on the next matching Notify, so tests need
+// not race the code under test.
+//
+// Exit records the exit code and ends the calling goroutine with
+// runtime.Goexit instead of terminating the test binary.
+//
+// FakeSignals is safe for concurrent use.
+type FakeSignals struct {
+	mu      sync.Mutex
+	subs    map[chan<- os.Signal][]os.Signal
+	pending []os.Signal
+	code    int
+	exited  chan struct{}
+	once    sync.Once
+}
+
+// NewFakeSignals returns a FakeSignals with no subscribers.
+func NewFakeSignals() *FakeSignals {
+	return &FakeSignals{
+		subs:   make(map[chan<- os.Signal][]os.Signal),
+		exited: make(chan struct{}),
+	}
+}
+
+// Notify implements [toolkit.SignalSource].
+func (f *FakeSignals) Notify(c chan<- os.Signal, sig ...os.Signal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subs[c] = append(f.subs[c], sig...)
+
+	var held []os.Signal
+	for _, s := range f.pending {
+		if slices.Contains(sig, s) {
+			deliver(c, s)
+			continue
+		}
+		held = append(held, s)
+	}
+	f.pending = held
+}
+
+// Stop implements [toolkit.SignalSource].
+func (f *FakeSignals) Stop(c chan<- os.Signal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subs, c)
+}
+
+// Exit implements [toolkit.SignalSource]. It records code, closes the
+// channel returned by [FakeSignals.Exited] and calls runtime.Goexit.
+func (f *FakeSignals) Exit(code int) {
+	f.once.Do(func() {
+		f.mu.Lock()
+		f.code = code
+		f.mu.Unlock()
+		close(f.exited)
+	})
+	runtime.Goexit()
+}
+
+// Send delivers sig to every subscriber that asked for it. Like the os/signal
+// package, delivery never blocks: a subscriber with a full channel misses the
+// signal. With no matching subscriber the signal is held until one appears.
+func (f *FakeSignals) Send(sig os.Signal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delivered := false
+	for c, sigs := range f.subs {
+		if slices.Contains(sigs, sig) {
+			deliver(c, sig)
+			delivered = true
+		}
+	}
+	if !delivered {
+		f.pending = append(f.pending, sig)
+	}
+}
+
+// Exited returns a channel closed when Exit is first called.
+func (f *FakeSignals) Exited() <-chan struct{} {
+	return f.exited
+}
+
+// ExitCode returns the code passed to Exit and whether Exit was called.
+func (f *FakeSignals) ExitCode() (int, bool) {
+	select {
+	case <-f.exited:
+	default:
+		return 0, false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.code, true
+}
+
+func deliver(c chan<- os.Signal, sig os.Signal) {
+	select {
+	case c <- sig:
+	default:
+	}
+}
+
+var _ toolkit.SignalSource = (*FakeSignals)(nil)
@@ -0,0 +1,280 @@
+// Package bundle writes and reads debug bundles: gzipped tarballs describing
+// the effective runtime of a CLI invocation, for attaching to bug reports.
+//
+// A bundle always holds runtime.json, the [toolkit.RuntimeDescription] with
+// sensitive environment values redacted. Options add the resolved
+// [appctx.AppPaths] (paths.json), the provenance of configuration cascades
+// (config.json) and arbitrary files such as logs (files/NAME).
+//
+// A typical --debug-bundle flag handler:
+//
+//	name := bundle.DefaultName(rt)
+//	err := bundle.WriteFile(rt, name,
+//		bundle.WithAppPaths(paths),
+//		bundle.WithConfig("config", resolved),
+//	)
+//
+// sandbox.FromBundle recreates an equivalent sandbox from a bundle so the
+// report can be reproduced in a test.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/appctx"
+	"github.com/jlrickert/cli-toolkit/cfgcascade"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// Archive member names.
+const (
+	RuntimeFile = "runtime.json"
+	PathsFile   = "paths.json"
+	ConfigFile  = "config.json"
+	FilesDir    = "files/"
+)
+
+// Bundle is the decoded content of a debug bundle.
+type Bundle struct {
+	Runtime toolkit.RuntimeDescription
+	Paths   *appctx.AppPaths
+	Config  []Config
+	// Files holds extra files keyed by name, without the files/ prefix.
+	Files map[string][]byte
+}
+
+// Config records how one configuration cascade was resolved.
+type Config struct {
+	Name string `json:"name"`
+	// Sources lists contributing providers, most specific first.
+	Sources []string `json:"sources"`
+	// Errors lists providers that failed, as "name: error".
+	Errors []string `json:"errors,omitempty"`
+	// Value is the merged value encoded as JSON, when it could be encoded.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Option configures a bundle built by [New].
+type Option func(*Bundle)
+
+// WithAppPaths records the resolved application paths.
+func WithAppPaths(p *appctx.AppPaths) Option {
+	return func(b *Bundle) {
+		if p != nil {
+			paths := *p
+			b.Paths = &paths
+		}
+	}
+}
+
+// WithConfig records the provenance of a resolved configuration cascade
+// under name. The merged value is included as JSON; omit sensitive fields
+// from T's JSON encoding or redact them before calling.
+func WithConfig[T any](name string, rv *cfgcascade.ResolvedValue[T]) Option {
+	return func(b *Bundle) {
+		if rv == nil {
+			return
+		}
+		c := Config{Name: name, Sources: slices.Clone(rv.Sources)}
+		for _, pe := range rv.Errors {
+			c.Errors = append(c.Errors, pe.Error())
+		}
+		if data, err := json.Marshal(rv.Value); err == nil {
+			c.Value = data
+		}
+		b.Config = append(b.Config, c)
+	}
+}
+
+// WithFile adds a file such as a log to the bundle under files/NAME.
+func WithFile(name string, data []byte) Option {
+	return func(b *Bundle) {
+		if b.Files == nil {
+			b.Files = map[string][]byte{}
+		}
+		b.Files[name] = bytes.Clone(data)
+	}
+}
+
+// WithRedactKeys redacts additional environment variables by exact name,
+// for application secrets that [toolkit.IsSensitiveEnv] does not recognize.
+func WithRedactKeys(keys ...string) Option {
+	return func(b *Bundle) {
+		for _, k := range keys {
+			if v, ok := b.Runtime.Env[k]; ok && v != "" {
+				b.Runtime.Env[k] = toolkit.Redacted
+			}
+		}
+	}
+}
+
+// New describes rt and applies opts.
+func New(rt *toolkit.Runtime, opts ...Option) *Bundle {
+	b := &Bundle{Runtime: rt.Describe()}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	return b
+}
+
+// DefaultName returns a bundle file name built from the command name and the
+// runtime clock, such as "tool-debug-20251015T123000Z.tar.gz".
+func DefaultName(rt *toolkit.Runtime) string {
+	name := "debug"
+	if p := rt.Process(); p != nil && p.Name != "" {
+		name = p.Name + "-debug"
+	}
+	stamp := rt.Clock().Now().UTC().Format("20060102T150405Z")
+	return name + "-" + stamp + ".tar.gz"
+}
+
+// Write writes b to w as a gzipped tarball. Member timestamps use the
+// described clock time so bundles are reproducible.
+func (b *Bundle) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	mtime := b.Runtime.Now
+	if mtime.IsZero() {
+		mtime = time.Unix(0, 0)
+	}
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: mtime,
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	if err := addJSON(RuntimeFile, b.Runtime); err != nil {
+		return fmt.Errorf("bundle: write %s: %w", RuntimeFile, err)
+	}
+	if b.Paths != nil {
+		if err := addJSON(PathsFile, b.Paths); err != nil {
+			return fmt.Errorf("bundle: write %s: %w", PathsFile, err)
+		}
+	}
+	if len(b.Config) > 0 {
+		if err := addJSON(ConfigFile, b.Config); err != nil {
+			return fmt.Errorf("bundle: write %s: %w", ConfigFile, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(b.Files)) {
+		if err := add(FilesDir+name, b.Files[name]); err != nil {
+			return fmt.Errorf("bundle: write %s: %w", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+// WriteFile builds a bundle for rt and writes it atomically to the runtime
+// path name.
+func WriteFile(rt *toolkit.Runtime, name string, opts ...Option) error {
+	var buf bytes.Buffer
+	if err := New(rt, opts...).Write(&buf); err != nil {
+		return err
+	}
+	if err := rt.AtomicWriteFile(name, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+// ErrNoRuntime is returned by [Read] when the archive has no runtime.json.
+var ErrNoRuntime = errors.New("bundle: missing " + RuntimeFile)
+
+// Read decodes a bundle written by [Bundle.Write].
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+	defer gz.Close()
+
+	b := &Bundle{}
+	haveRuntime := false
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: read %s: %w", hdr.Name, err)
+		}
+
+		name := path.Clean(hdr.Name)
+		switch {
+		case name == RuntimeFile:
+			err = json.Unmarshal(data, &b.Runtime)
+			haveRuntime = true
+		case name == PathsFile:
+			b.Paths = &appctx.AppPaths{}
+			err = json.Unmarshal(data, b.Paths)
+		case name == ConfigFile:
+			err = json.Unmarshal(data, &b.Config)
+		case strings.HasPrefix(name, FilesDir):
+			if b.Files == nil {
+				b.Files = map[string][]byte{}
+			}
+			b.Files[strings.TrimPrefix(name, FilesDir)] = data
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bundle: decode %s: %w", name, err)
+		}
+	}
+	if !haveRuntime {
+		return nil, ErrNoRuntime
+	}
+	return b, nil
+}
+
+// ReadFile reads the bundle at the runtime path name.
+func ReadFile(rt *toolkit.Runtime, name string) (*Bundle, error) {
+	data, err := rt.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+	return Read(bytes.NewReader(data))
+}
@@ -0,0 +1,91 @@
+package bundle_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/appctx"
+	"github.com/jlrickert/cli-toolkit/cfgcascade"
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type config struct {
+	Level string `json:"level"`
+}
+
+func resolved() *cfgcascade.ResolvedValue[config] {
+	c := &cfgcascade.Cascade[config]{
+		Layers: []cfgcascade.Layer[config]{
+			{Rank: 0, Provider: &cfgcascade.DefaultProvider[config]{ProviderName: "defaults", Default: config{Level: "info"}}},
+			{Rank: 1, Provider: &cfgcascade.FuncProvider[config]{
+				ProviderName: "user",
+				Fn: func(func(string) string) (config, error) {
+					return config{}, errors.New("bad yaml")
+				},
+			}},
+			{Rank: 2, Provider: &cfgcascade.DefaultProvider[config]{ProviderName: "env", Default: config{Level: "debug"}}},
+		},
+		MergeFn: func(_, overlay config) config { return overlay },
+	}
+	return c.Resolve(func(string) string { return "" })
+}
+
+func TestWriteFile_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil, tu.WithEnv("API_TOKEN", "secret"), tu.WithEnv("APP_PIN", "1234"))
+	rt := sb.Runtime()
+	rt.SetProcessInfo(toolkit.ProcessInfo{Name: "tool", UID: "inv-1"})
+	paths, err := appctx.NewAppPaths(rt, "/repo", "tool")
+	require.NoError(t, err)
+
+	name := bundle.DefaultName(rt)
+	assert.Equal(t, "tool-debug-20251015T123000Z.tar.gz", name)
+	require.NoError(t, bundle.WriteFile(rt, name,
+		bundle.WithAppPaths(paths),
+		bundle.WithConfig("config", resolved()),
+		bundle.WithFile("tool.log", []byte("line\n")),
+		bundle.WithRedactKeys("APP_PIN"),
+	))
+
+	b, err := bundle.ReadFile(rt, name)
+	require.NoError(t, err)
+	assert.Equal(t, toolkit.Redacted, b.Runtime.Env["API_TOKEN"])
+	assert.Equal(t, toolkit.Redacted, b.Runtime.Env["APP_PIN"])
+	assert.Equal(t, "/home/testuser", b.Runtime.Wd)
+	require.NotNil(t, b.Paths)
+	assert.Equal(t, paths.ConfigRoot, b.Paths.ConfigRoot)
+	require.Len(t, b.Config, 1)
+	assert.Equal(t, []string{"env", "defaults"}, b.Config[0].Sources)
+	assert.Equal(t, []string{"user: bad yaml"}, b.Config[0].Errors)
+	assert.JSONEq(t, `{"level":"debug"}`, string(b.Config[0].Value))
+	assert.Equal(t, []byte("line\n"), b.Files["tool.log"])
+}
+
+func TestWrite_IsReproducible(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	var first, second bytes.Buffer
+	require.NoError(t, bundle.New(sb.Runtime()).Write(&first))
+	require.NoError(t, bundle.New(sb.Runtime()).Write(&second))
+	assert.Equal(t, first.Bytes(), second.Bytes())
+}
+
+func TestRead_RequiresRuntime(t *testing.T) {
+	t.Parallel()
+
+	_, err := bundle.Read(bytes.NewReader(nil))
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	b := &bundle.Bundle{}
+	require.NoError(t, b.Write(&buf))
+	_, err = bundle.Read(&buf)
+	require.NoError(t, err)
+}
@@ -0,0 +1,196 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Commander executes external commands on behalf of a [Runtime].
+//
+// [OsCommander] runs real processes via os/exec. Tests can install a scripted
+// implementation (see sandbox.FakeCommander) with [WithRuntimeCommander] so
+// code that shells out can be exercised without touching the host.
+type Commander interface {
+	// Run executes cmd and blocks until it completes. A command that starts
+	// but exits with a non-zero status must be reported as an [*ExitError].
+	Run(ctx context.Context, cmd *Command) error
+}
+
+// Command describes a single external command invocation. Build one with
+// [Runtime.Command], adjust the exported fields if needed, then call Run,
+// Output, or CombinedOutput.
+type Command struct {
+	// Name is the program to run, as passed to Runtime.Command.
+	Name string
+	// Path is the host path of the program. Runtime.Command sets it when
+	// [Runtime.LookPath] finds Name; when empty, [OsCommander] resolves Name
+	// against the PATH in Env, relative to Dir.
+	Path string
+	// Args holds the command arguments, not including Name.
+	Args []string
+	// Dir is the host working directory. Runtime.Command sets it to the
+	// runtime working directory translated under the jail.
+	Dir string
+	// Env holds the child environment in "KEY=VALUE" form.
+	Env []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	ctx       context.Context
+	commander Commander
+	err       error
+}
+
+// Argv returns Name followed by Args.
+func (c *Command) Argv() []string {
+	argv := make([]string, 0, len(c.Args)+1)
+	argv = append(argv, c.Name)
+	return append(argv, c.Args...)
+}
+
+// Context returns the context the command was created with.
+func (c *Command) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Run executes the command using the runtime Commander.
+func (c *Command) Run() error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.commander == nil {
+		return fmt.Errorf("command %q: no commander configured", c.Name)
+	}
+	return c.commander.Run(c.Context(), c)
+}
+
+// Output runs the command and returns its standard output. Standard error is
+// captured rather than forwarded to the runtime stream; when the command
+// exits non-zero it is available as [ExitError.Stderr].
+func (c *Command) Output() ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err := c.Run()
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its combined standard output
+// and standard error.
+func (c *Command) CombinedOutput() ([]byte, error) {
+	var b bytes.Buffer
+	c.Stdout = &b
+	c.Stderr = &b
+	err := c.Run()
+	return b.Bytes(), err
+}
+
+// ExitError reports a command that ran but exited with a non-zero status.
+type ExitError struct {
+	// Code is the process exit code.
+	Code int
+	// Stderr holds captured standard error when the command was run with
+	// Command.Output.
+	Stderr []byte
+	// Err is the underlying error, such as an *exec.ExitError. It may be nil
+	// for scripted commanders.
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the child process exit code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// OsCommander is the production Commander backed by os/exec.
+type OsCommander struct{}
+
+// Run executes cmd with exec.CommandContext. The program is cmd.Path, or Name
+// looked up in the PATH from cmd.Env rather than the host process PATH.
+// Non-zero exits are converted to [*ExitError] wrapping the original
+// *exec.ExitError.
+func (OsCommander) Run(ctx context.Context, c *Command) error {
+	path := c.Path
+	if path == "" {
+		p, err := lookPathIn(c.Name, c.Env, c.Dir)
+		if err != nil {
+			return err
+		}
+		path = p
+	}
+	cmd := exec.CommandContext(ctx, path, c.Args...)
+	cmd.Args[0] = c.Name
+	cmd.Dir = c.Dir
+	cmd.Env = c.Env
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ExitError{Code: exitErr.ExitCode(), Stderr: exitErr.Stderr, Err: err}
+	}
+	return err
+}
+
+// lookPathIn resolves name like exec.LookPath, but searches the PATH from env
+// and resolves relative names and PATH entries against dir, so the host lookup
+// follows the runtime environment rather than the current process. A nil env
+// uses the process PATH, as os/exec does.
+func lookPathIn(name string, env []string, dir string) (string, error) {
+	if strings.ContainsAny(name, `/\`) {
+		return exec.LookPath(absIn(dir, name))
+	}
+	pathList := os.Getenv("PATH")
+	if env != nil {
+		pathList = ""
+		for _, kv := range env {
+			if v, ok := strings.CutPrefix(kv, "PATH="); ok {
+				pathList = v
+			}
+		}
+	}
+	for _, d := range filepath.SplitList(pathList) {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		if p, err := exec.LookPath(absIn(dir, filepath.Join(d, name))); err == nil {
+			return p, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+func absIn(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+var _ Commander = (*OsCommander)(nil)
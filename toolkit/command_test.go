@@ -0,0 +1,129 @@
+package toolkit_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingCommander struct {
+	cmd *toolkit.Command
+}
+
+func (r *recordingCommander) Run(ctx context.Context, cmd *toolkit.Command) error {
+	r.cmd = cmd
+	return nil
+}
+
+func TestRuntime_CommandUsesRuntimeState(t *testing.T) {
+	t.Parallel()
+
+	jail := t.TempDir()
+	rec := &recordingCommander{}
+	rt, err := toolkit.NewTestRuntime(jail, "/home/alice", "alice",
+		toolkit.WithRuntimeCommander(rec))
+	require.NoError(t, err)
+	require.NoError(t, rt.Set("GREETING", "hi"))
+
+	var out bytes.Buffer
+	require.NoError(t, rt.SetStream(&toolkit.Stream{Out: &out}))
+
+	require.NoError(t, rt.Command(t.Context(), "tool", "a", "b").Run())
+	require.NotNil(t, rec.cmd)
+	assert.Equal(t, []string{"tool", "a", "b"}, rec.cmd.Argv())
+	assert.Equal(t, filepath.Join(jail, "home", "alice"), rec.cmd.Dir)
+	assert.Contains(t, rec.cmd.Env, "GREETING=hi")
+	assert.Contains(t, rec.cmd.Env, "PWD="+filepath.Join(jail, "home", "alice"))
+	assert.Same(t, &out, rec.cmd.Stdout)
+}
+
+func TestRuntime_HostPath(t *testing.T) {
+	t.Parallel()
+
+	jail := t.TempDir()
+	rt, err := toolkit.NewTestRuntime(jail, "/home/alice", "alice")
+	require.NoError(t, err)
+
+	p, err := rt.HostPath("~/notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(jail, "home", "alice", "notes.txt"), p)
+
+	osRt, err := toolkit.NewRuntime(toolkit.WithRuntimeEnv(toolkit.NewTestEnv("", "/home/bob", "bob")))
+	require.NoError(t, err)
+	p, err = osRt.HostPath("/etc/hosts")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/etc/hosts"), p)
+}
+
+func TestOsCommander_ExitError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	t.Parallel()
+
+	jail := t.TempDir()
+	rt, err := toolkit.NewTestRuntime(jail, "", "")
+	require.NoError(t, err)
+	require.NoError(t, rt.Mkdir("~", 0o755, true))
+
+	out, err := rt.Command(t.Context(), "/bin/sh", "-c", "pwd; echo oops >&2; exit 3").Output()
+	var exitErr *toolkit.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.Code)
+	assert.Equal(t, "oops", strings.TrimSpace(string(exitErr.Stderr)))
+
+	wantDir, err := filepath.EvalSymlinks(filepath.Join(jail, "home", "testuser"))
+	require.NoError(t, err)
+	gotDir, err := filepath.EvalSymlinks(strings.TrimSpace(string(out)))
+	require.NoError(t, err)
+	assert.Equal(t, wantDir, gotDir)
+}
+
+func TestOsCommander_UsesRuntimePathAndDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	t.Parallel()
+
+	home := t.TempDir()
+	rt, err := toolkit.NewRuntime(
+		toolkit.WithRuntimeEnv(toolkit.NewTestEnv("", home, "alice")),
+		toolkit.WithRuntimeFileSystem(&toolkit.OsFS{}),
+	)
+	require.NoError(t, err)
+
+	bin := filepath.Join(home, "bin")
+	require.NoError(t, os.MkdirAll(bin, 0o755))
+	script := []byte("#!/bin/sh\necho runtime-tool \"$@\"\n")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "rt-only-tool"), script, 0o755))
+	require.NoError(t, rt.Set("PATH", bin+string(filepath.ListSeparator)+os.Getenv("PATH")))
+
+	cmd := rt.Command(t.Context(), "rt-only-tool", "x")
+	want, err := rt.LookPath("rt-only-tool")
+	require.NoError(t, err)
+	assert.Equal(t, want, cmd.Path)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "runtime-tool x\n", string(out))
+
+	// Relative names resolve against the runtime working directory.
+	require.NoError(t, rt.Setwd(home))
+	out, err = rt.Command(t.Context(), "./bin/rt-only-tool", "y").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "runtime-tool y\n", string(out))
+
+	// Without a resolved Path, OsCommander still searches the runtime PATH.
+	cmd = rt.Command(t.Context(), "rt-only-tool", "z")
+	cmd.Path = ""
+	out, err = cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "runtime-tool z\n", string(out))
+}
@@ -0,0 +1,59 @@
+package toolkit
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/jlrickert/cli-toolkit/mylog"
+)
+
+type runtimeKey struct{}
+
+// WithRuntime returns a copy of ctx carrying rt.
+//
+// Prefer passing a Runtime explicitly. WithRuntime is for code several layers
+// below the command that only receives a context, such as callbacks and
+// third-party interfaces.
+func WithRuntime(ctx context.Context, rt *Runtime) context.Context {
+	return context.WithValue(ctx, runtimeKey{}, rt)
+}
+
+// RuntimeFrom returns the runtime carried by ctx. When ctx has none, it
+// returns a process-wide runtime built with [NewOsRuntime] on first use. The
+// fallback is shared, so callers that change its environment or working
+// directory should [Runtime.Fork] it first.
+func RuntimeFrom(ctx context.Context) *Runtime {
+	if ctx != nil {
+		if rt, ok := ctx.Value(runtimeKey{}).(*Runtime); ok && rt != nil {
+			return rt
+		}
+	}
+	return fallbackRuntime()
+}
+
+var fallbackRuntime = sync.OnceValue(func() *Runtime {
+	if rt, err := NewOsRuntime(); err == nil {
+		return rt
+	}
+	// NewOsRuntime only fails when the working directory cannot be read.
+	// Keep the OS defaults without normalizing state.
+	rt := defaultRuntime()
+	rt.wd = string(filepath.Separator)
+	rt.signals = newSignals(rt)
+	return rt
+})
+
+// LoggerFrom returns the logger of the runtime carried by ctx with the
+// runtime [Runtime.LogAttrs] and the attributes attached with
+// [mylog.WithAttrs] already applied.
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	rt := RuntimeFrom(ctx)
+	attrs := append(rt.LogAttrs(), mylog.Attrs(ctx)...)
+	lg := rt.Logger()
+	if len(attrs) == 0 {
+		return lg
+	}
+	return slog.New(lg.Handler().WithAttrs(attrs))
+}
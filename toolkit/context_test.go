@@ -0,0 +1,62 @@
+package toolkit_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/mylog"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeFrom_ReturnsAttachedRuntime(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser")
+	require.NoError(t, err)
+
+	ctx := toolkit.WithRuntime(context.Background(), rt)
+	assert.Same(t, rt, toolkit.RuntimeFrom(ctx))
+}
+
+func TestRuntimeFrom_FallsBackToOsRuntime(t *testing.T) {
+	t.Parallel()
+
+	rt := toolkit.RuntimeFrom(context.Background())
+	require.NotNil(t, rt)
+	require.NoError(t, rt.Validate())
+	assert.IsType(t, &toolkit.OsEnv{}, rt.Env())
+	assert.Same(t, rt, toolkit.RuntimeFrom(context.Background()))
+}
+
+func TestLoggerFrom_IncludesInvocationAttrs(t *testing.T) {
+	t.Parallel()
+
+	lg, th := mylog.NewTestLogger(t, slog.LevelDebug)
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser",
+		toolkit.WithRuntimeLogger(lg),
+		toolkit.WithProcessInfo(toolkit.ProcessInfo{Name: "tool", UID: "inv-1"}),
+	)
+	require.NoError(t, err)
+
+	ctx := toolkit.WithRuntime(context.Background(), rt)
+	ctx = mylog.WithAttrs(ctx, slog.String("step", "sync"))
+	toolkit.LoggerFrom(ctx).Info("deep call")
+
+	entries := mylog.FindEntries(th, func(e mylog.LoggedEntry) bool { return e.Msg == "deep call" })
+	require.Len(t, entries, 1)
+	assert.Equal(t, "tool", entries[0].Attrs[toolkit.LogKeyCommand])
+	assert.Equal(t, "inv-1", entries[0].Attrs[toolkit.LogKeyInvocation])
+	assert.Equal(t, "sync", entries[0].Attrs["step"])
+}
+
+func TestRuntime_LogAttrsWithoutProcessInfo(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser")
+	require.NoError(t, err)
+	assert.Nil(t, rt.LogAttrs())
+	assert.Empty(t, toolkit.AnyAttrs(rt.LogAttrs()))
+}
@@ -0,0 +1,127 @@
+package toolkit
+
+import (
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Redacted replaces sensitive environment values in a [RuntimeDescription].
+const Redacted = "[REDACTED]"
+
+// RuntimeDescription is a snapshot of the effective runtime, suitable for
+// bug reports and debug bundles.
+type RuntimeDescription struct {
+	// Env is the environment with sensitive values replaced by [Redacted].
+	Env  map[string]string `json:"env"`
+	Jail string            `json:"jail"`
+	Wd   string            `json:"wd"`
+	Home string            `json:"home,omitempty"`
+	User string            `json:"user,omitempty"`
+	// EnvName is the Env implementation name, such as "os-env".
+	EnvName string `json:"envName"`
+	// Now is the runtime clock time when the description was taken.
+	Now time.Time `json:"now"`
+	// Location is the runtime time zone name, such as "UTC" or
+	// "America/New_York".
+	Location string       `json:"location,omitempty"`
+	Process  *ProcessInfo `json:"process,omitempty"`
+	Terminal Terminal     `json:"terminal"`
+
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Describe returns a snapshot of the runtime state. Environment values whose
+// keys look sensitive (see [IsSensitiveEnv]) are replaced by [Redacted], and
+// passwords embedded in URL values are masked.
+func (rt *Runtime) Describe() RuntimeDescription {
+	d := RuntimeDescription{
+		Env:       map[string]string{},
+		Jail:      rt.GetJail(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		Terminal:  rt.Terminal(),
+	}
+	if rt == nil {
+		return d
+	}
+	d.Wd, _ = rt.Getwd()
+	if rt.env != nil {
+		d.EnvName = rt.env.Name()
+		d.Home, _ = rt.env.GetHome()
+		d.User, _ = rt.env.GetUser()
+		d.Env = RedactEnv(rt.env.Environ())
+	}
+	if rt.clock != nil {
+		d.Now = rt.Now()
+	}
+	d.Location = rt.Location().String()
+	if rt.process != nil {
+		p := *rt.process
+		d.Process = &p
+	}
+	return d
+}
+
+// sensitiveEnvWords are key segments, split on "_", that mark a variable as
+// secret.
+var sensitiveEnvWords = []string{
+	"AUTH", "APIKEY", "COOKIE", "CREDENTIAL", "CREDENTIALS", "KEY",
+	"PASS", "PASSPHRASE", "PASSWD", "PRIVATE", "SECRET", "TOKEN",
+}
+
+// IsSensitiveEnv reports whether an environment variable named key is likely
+// to hold a secret, such as GITHUB_TOKEN, AWS_SECRET_ACCESS_KEY or
+// DB_PASSWORD.
+func IsSensitiveEnv(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, word := range []string{"TOKEN", "SECRET", "PASSWORD"} {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	for seg := range strings.SplitSeq(upper, "_") {
+		if slices.Contains(sensitiveEnvWords, seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactEnv converts environ entries of the form "KEY=VALUE" to a map,
+// replacing sensitive values with [Redacted] and masking URL passwords.
+func RedactEnv(environ []string) map[string]string {
+	m := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			continue
+		}
+		m[key] = RedactEnvValue(key, value)
+	}
+	return m
+}
+
+// RedactEnvValue returns value, or [Redacted] when key is sensitive. A URL
+// value with a password in its user info has the password masked.
+func RedactEnvValue(key, value string) string {
+	if value == "" {
+		return value
+	}
+	if IsSensitiveEnv(key) {
+		return Redacted
+	}
+	if strings.Contains(value, "@") && strings.Contains(value, "://") {
+		if u, err := url.Parse(value); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				return u.Redacted()
+			}
+		}
+	}
+	return value
+}
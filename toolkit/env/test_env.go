@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/jlrickert/cli-toolkit/toolkit/jail"
 )
@@ -18,7 +19,11 @@ import (
 // The home and user fields satisfy GetHome and GetUser. The data map stores
 // other keys. For convenience, setting or unsetting the keys "HOME" and
 // "USER" updates the corresponding home and user fields.
+//
+// TestEnv is safe for concurrent use.
 type TestEnv struct {
+	mu sync.RWMutex
+
 	jail string
 	home string // home is an absolute path. Doesn't include the jail.
 	user string
@@ -96,10 +101,15 @@ func NewTestEnv(jailPath, home, username string) *TestEnv {
 }
 
 func (env *TestEnv) GetJail() string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
 	return env.jail
 }
 
 func (env *TestEnv) SetJail(jailPath string) error {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+
 	newJail := jail.CleanJail(jailPath)
 	oldJail := env.jail
 	env.jail = newJail
@@ -136,6 +146,12 @@ func (env *TestEnv) SetJail(jailPath string) error {
 // configured jail when possible. This helps keep tests hermetic by ensuring
 // paths used for home are under the test temporary area.
 func (env *TestEnv) GetHome() (string, error) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	return env.getHomeLocked()
+}
+
+func (env *TestEnv) getHomeLocked() (string, error) {
 	if env.home == "" {
 		return "", errors.New("home not set in TestEnv")
 	}
@@ -145,7 +161,13 @@ func (env *TestEnv) GetHome() (string, error) {
 // SetHome sets the TestEnv home directory and updates the "HOME" key in the
 // underlying map for callers that read via Get.
 func (env *TestEnv) SetHome(rel string) error {
-	path, err := env.ResolvePath(rel, false)
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	return env.setHomeLocked(rel)
+}
+
+func (env *TestEnv) setHomeLocked(rel string) error {
+	path, err := env.resolvePathLocked(rel, false)
 	if err != nil {
 		return fmt.Errorf("unable to set home: %w", err)
 	}
@@ -160,6 +182,8 @@ func (env *TestEnv) SetHome(rel string) error {
 
 // GetUser returns the configured username or an error if it is not set.
 func (env *TestEnv) GetUser() (string, error) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
 	if env.user == "" {
 		return "", errors.New("user not set in TestEnv")
 	}
@@ -169,6 +193,12 @@ func (env *TestEnv) GetUser() (string, error) {
 // SetUser sets the TestEnv current user and updates the "USER" key in the
 // underlying map for callers that use Get.
 func (env *TestEnv) SetUser(username string) error {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	return env.setUserLocked(username)
+}
+
+func (env *TestEnv) setUserLocked(username string) error {
 	env.user = username
 	if env.data == nil {
 		env.data = make(map[string]string)
@@ -180,7 +210,9 @@ func (env *TestEnv) SetUser(username string) error {
 // Get returns the stored value for key. Reading from a nil map returns the
 // zero value, so this method is safe on a zero TestEnv. The special keys HOME
 // and USER come from dedicated fields.
-func (env TestEnv) Get(key string) string {
+func (env *TestEnv) Get(key string) string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
 	switch key {
 	case "HOME":
 		return env.home
@@ -195,13 +227,15 @@ func (env TestEnv) Get(key string) string {
 // corresponding dedicated field is updated. Calling Set on a nil receiver
 // returns an error rather than panicking.
 func (env *TestEnv) Set(key string, value string) error {
+	env.mu.Lock()
+	defer env.mu.Unlock()
 	switch key {
 	case "HOME":
-		return env.SetHome(value)
+		return env.setHomeLocked(value)
 	case "USER":
-		return env.SetUser(value)
+		return env.setUserLocked(value)
 	case "PWD":
-		return env.Setwd(value)
+		return env.setwdLocked(value)
 	default:
 		if env.data == nil {
 			env.data = make(map[string]string)
@@ -214,6 +248,9 @@ func (env *TestEnv) Set(key string, value string) error {
 // Environ returns a slice of "KEY=VALUE" entries representing the environment
 // stored in the TestEnv. It guarantees HOME and USER are present when set.
 func (env *TestEnv) Environ() []string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
 	// Collect keys from the backing map and ensure HOME/USER are present
 	// based on dedicated fields so callers get a complete view.
 	keys := make([]string, 0, len(env.data)+2)
@@ -253,6 +290,8 @@ func (env *TestEnv) Environ() []string {
 
 // Has reports whether the given key is present in the TestEnv map.
 func (env *TestEnv) Has(key string) bool {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
 	_, ok := env.data[key]
 	return ok
 }
@@ -260,6 +299,8 @@ func (env *TestEnv) Has(key string) bool {
 // Unset removes a key from the TestEnv. If key is "HOME" or "USER" the
 // corresponding field is cleared. Calling Unset on a nil receiver is a no-op.
 func (env *TestEnv) Unset(key string) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
 	switch key {
 	case "HOME":
 		env.home = ""
@@ -289,6 +330,9 @@ func (env *TestEnv) Unset(key string) {
 // The returned path will be adjusted to reside inside the configured jail
 // when possible to keep test artifacts contained.
 func (env *TestEnv) GetTempDir() string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
 	// Prefer explicit TMPDIR/TEMP/TMP if provided in the TestEnv.
 	if d := env.data["TMPDIR"]; d != "" {
 		return d
@@ -327,6 +371,12 @@ func (env *TestEnv) GetTempDir() string {
 
 // Getwd returns the TestEnv's PWD value if set, otherwise an error.
 func (env *TestEnv) Getwd() (string, error) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	return env.getwdLocked()
+}
+
+func (env *TestEnv) getwdLocked() (string, error) {
 	if env.data != nil {
 		if wd := env.data["PWD"]; wd != "" {
 			return wd, nil
@@ -337,10 +387,16 @@ func (env *TestEnv) Getwd() (string, error) {
 
 // Setwd sets the TestEnv's PWD value to the provided directory.
 func (env *TestEnv) Setwd(dir string) error {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	return env.setwdLocked(dir)
+}
+
+func (env *TestEnv) setwdLocked(dir string) error {
 	if env.data == nil {
 		env.data = make(map[string]string)
 	}
-	path, err := env.ResolvePath(dir, false)
+	path, err := env.resolvePathLocked(dir, false)
 	if err != nil {
 		return err
 	}
@@ -359,6 +415,12 @@ func (env *TestEnv) Setwd(dir string) error {
 // uses the TestEnv GetHome value. If home is not set, expansion may produce
 // an empty or unexpected result.
 func (env *TestEnv) ExpandPath(p string) string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	return env.expandPathLocked(p)
+}
+
+func (env *TestEnv) expandPathLocked(p string) string {
 	if p == "" {
 		return p
 	}
@@ -368,7 +430,7 @@ func (env *TestEnv) ExpandPath(p string) string {
 
 	// Only expand the simple leading tilde forms: "~" or "~/" or "~\\".
 	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
-		home, _ := env.GetHome()
+		home, _ := env.getHomeLocked()
 		if p == "~" {
 			return filepath.Clean(home)
 		}
@@ -384,19 +446,25 @@ func (env *TestEnv) ExpandPath(p string) string {
 }
 
 func (env *TestEnv) ResolvePath(rel string, follow bool) (string, error) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	return env.resolvePathLocked(rel, follow)
+}
+
+func (env *TestEnv) resolvePathLocked(rel string, follow bool) (string, error) {
 	p := filepath.Clean(rel)
 	if p == "." || p == "" {
-		return env.Getwd()
+		return env.getwdLocked()
 	}
 
 	// Expand the path (handles ~ and env vars).
-	expanded := env.ExpandPath(rel)
+	expanded := env.expandPathLocked(rel)
 
 	var path string
 	if filepath.IsAbs(expanded) {
 		path = expanded
 	} else {
-		wd, err := env.Getwd()
+		wd, err := env.getwdLocked()
 		if err != nil {
 			return "", err
 		}
@@ -420,6 +488,8 @@ func (env *TestEnv) Clone() *TestEnv {
 	if env == nil {
 		return nil
 	}
+	env.mu.RLock()
+	defer env.mu.RUnlock()
 
 	var dataCopy map[string]string
 	if env.data != nil {
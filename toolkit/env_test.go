@@ -1,8 +1,10 @@
 package toolkit_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/jlrickert/cli-toolkit/toolkit"
@@ -155,3 +157,37 @@ func TestOsEnvGlob(t *testing.T) {
 	}
 	assert.ElementsMatch(t, expectedFiles, matches)
 }
+
+func TestTestEnvConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	env := toolkit.NewTestEnv(t.TempDir(), "/home/testuser", "testuser")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("KEY_%d", i)
+			for j := range 50 {
+				assert.NoError(t, env.Set(key, fmt.Sprint(j)))
+				assert.Equal(t, fmt.Sprint(j), env.Get(key))
+				assert.NoError(t, env.Setwd(fmt.Sprintf("/dir/%d", i)))
+				_, _ = env.Getwd()
+				_ = env.Environ()
+				_ = env.ExpandPath("~/file")
+				_, _ = env.ResolvePath("rel", false)
+				_ = env.CloneEnv()
+				if j%10 == 0 {
+					assert.NoError(t, env.Set("HOME", "/home/testuser"))
+					env.Unset(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	home, err := env.GetHome()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/testuser", home)
+}
@@ -9,4 +9,5 @@ import (
 var (
 	ErrNoEnvKey      = errors.New("env key missing")
 	ErrEscapeAttempt = jailpkg.ErrEscapeAttempt
+	ErrShellSyntax   = errors.New("invalid shell syntax")
 )
@@ -0,0 +1,264 @@
+// Package errs classifies command errors into a small set of kinds, maps
+// them to process exit codes and renders them for users.
+//
+// Errors are classified through their wrap chain, so a kind attached deep in
+// a call stack survives later fmt.Errorf("...: %w", err) wrapping. Errors
+// without an explicit kind are classified from well-known causes: a missing
+// file is [NotFound], a permission error or jail escape is [Permission], a
+// cancelled context or signal is [Interrupted], and anything else is
+// [Internal].
+//
+//	if !exists {
+//		return errs.New(errs.NotFound, "no profile named %q", name).
+//			WithHint("List profiles with `tool profile ls`.")
+//	}
+//
+// At the top of a command, [Render] prints the message and hint to
+// Stream.Err, logs the full error, and returns the exit code:
+//
+//	os.Exit(errs.Render(rt, run(ctx, rt)))
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// Kind is the category of an error.
+type Kind int
+
+const (
+	// Unknown means the error does not carry a kind of its own; [KindOf]
+	// falls back to classifying its cause.
+	Unknown Kind = iota
+	// Usage means the command was invoked incorrectly: bad flags, arguments
+	// or input.
+	Usage
+	// NotFound means a named resource does not exist.
+	NotFound
+	// Permission means the operation is not allowed.
+	Permission
+	// Conflict means the operation clashes with existing state, such as a
+	// file that already exists or a held lock.
+	Conflict
+	// Internal is a bug or an unexpected failure.
+	Internal
+	// Interrupted means the user or a signal stopped the command.
+	Interrupted
+)
+
+// String returns the lower-case name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Usage:
+		return "usage"
+	case NotFound:
+		return "not-found"
+	case Permission:
+		return "permission"
+	case Conflict:
+		return "conflict"
+	case Internal:
+		return "internal"
+	case Interrupted:
+		return "interrupted"
+	default:
+		return "unknown"
+	}
+}
+
+// Exit codes returned by [ExitCode] for each kind.
+const (
+	ExitOK          = 0
+	ExitInternal    = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitPermission  = 4
+	ExitConflict    = 5
+	ExitInterrupted = 130
+)
+
+// ExitCode returns the exit code for k.
+func (k Kind) ExitCode() int {
+	switch k {
+	case Usage:
+		return ExitUsage
+	case NotFound:
+		return ExitNotFound
+	case Permission:
+		return ExitPermission
+	case Conflict:
+		return ExitConflict
+	case Interrupted:
+		return ExitInterrupted
+	default:
+		return ExitInternal
+	}
+}
+
+// Error is an error with a kind and an optional user-facing hint.
+type Error struct {
+	// Kind classifies the error. Unknown defers to the wrapped error.
+	Kind Kind
+	// Msg is the message shown to users. When empty, the wrapped error's
+	// message is used.
+	Msg string
+	// Hint suggests how to fix the problem, such as a command to run.
+	Hint string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+// New returns an error of kind k with a formatted message.
+func New(k Kind, format string, args ...any) *Error {
+	return &Error{Kind: k, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns err with kind k and message msg prepended, or nil when err is
+// nil.
+func Wrap(err error, k Kind, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: k, Msg: msg, Err: err}
+}
+
+// WithKind returns err classified as k, keeping its message, or nil when err
+// is nil.
+func WithKind(err error, k Kind) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: k, Err: err}
+}
+
+// WithHint attaches a hint to err without changing its message or kind. It
+// returns nil when err is nil.
+func WithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Hint: hint, Err: err}
+}
+
+// WithHint sets the hint on e and returns it.
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Msg == "" && e.Err == nil:
+		return e.Kind.String() + " error"
+	case e.Msg == "":
+		return e.Err.Error()
+	case e.Err == nil:
+		return e.Msg
+	default:
+		return e.Msg + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf classifies err. The first [*Error] in the wrap chain with a known
+// kind wins; otherwise the kind is inferred from well-known causes. KindOf
+// returns Unknown only for a nil error.
+func KindOf(err error) Kind {
+	if err == nil {
+		return Unknown
+	}
+	for e := range chain(err) {
+		if ke, ok := e.(*Error); ok && ke.Kind != Unknown {
+			return ke.Kind
+		}
+	}
+
+	var sigErr *toolkit.SignalError
+	switch {
+	case errors.As(err, &sigErr),
+		errors.Is(err, context.Canceled):
+		return Interrupted
+	case errors.Is(err, fs.ErrNotExist),
+		errors.Is(err, exec.ErrNotFound),
+		errors.Is(err, toolkit.ErrNoEnvKey):
+		return NotFound
+	case errors.Is(err, fs.ErrPermission),
+		errors.Is(err, toolkit.ErrEscapeAttempt):
+		return Permission
+	case errors.Is(err, fs.ErrExist):
+		return Conflict
+	}
+	return Internal
+}
+
+// Is reports whether err is classified as k.
+func Is(err error, k Kind) bool {
+	return KindOf(err) == k
+}
+
+// ExitCode returns the process exit code for err: 0 for nil, the code of an
+// error in the chain with an ExitCode method (such as a
+// [toolkit.SignalError] or a failed child process) when no kind was set
+// explicitly, and the kind's code otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	for e := range chain(err) {
+		if ke, ok := e.(*Error); ok && ke.Kind != Unknown {
+			return ke.Kind.ExitCode()
+		}
+		if ec, ok := e.(interface{ ExitCode() int }); ok {
+			if code := ec.ExitCode(); code > 0 {
+				return code
+			}
+		}
+	}
+	return KindOf(err).ExitCode()
+}
+
+// Hint returns the first hint found in the wrap chain of err.
+func Hint(err error) string {
+	for e := range chain(err) {
+		if ke, ok := e.(*Error); ok && ke.Hint != "" {
+			return ke.Hint
+		}
+	}
+	return ""
+}
+
+// chain yields err and every error it wraps, depth first.
+func chain(err error) func(yield func(error) bool) {
+	return func(yield func(error) bool) {
+		walk(err, yield)
+	}
+}
+
+func walk(err error, yield func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !yield(err) {
+		return false
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(x.Unwrap(), yield)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if !walk(e, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
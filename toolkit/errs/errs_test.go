@@ -0,0 +1,148 @@
+package errs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"syscall"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/mylog"
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindOf_Classifies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want errs.Kind
+	}{
+		{"nil", nil, errs.Unknown},
+		{"explicit", errs.New(errs.Usage, "bad flag %q", "--x"), errs.Usage},
+		{"wrapped explicit", fmt.Errorf("load: %w", errs.New(errs.Conflict, "locked")), errs.Conflict},
+		{"not exist", fmt.Errorf("read: %w", fs.ErrNotExist), errs.NotFound},
+		{"env key", toolkit.ErrNoEnvKey, errs.NotFound},
+		{"permission", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, errs.Permission},
+		{"escape", toolkit.ErrEscapeAttempt, errs.Permission},
+		{"exists", fs.ErrExist, errs.Conflict},
+		{"canceled", context.Canceled, errs.Interrupted},
+		{"signal", &toolkit.SignalError{Signal: syscall.SIGTERM}, errs.Interrupted},
+		{"other", errors.New("boom"), errs.Internal},
+		{"hint keeps cause", errs.WithHint(fs.ErrNotExist, "create it"), errs.NotFound},
+		{"override", errs.WithKind(fs.ErrNotExist, errs.Usage), errs.Usage},
+		{"joined", errors.Join(errors.New("a"), fs.ErrPermission), errs.Permission},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, errs.KindOf(tt.err))
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, errs.ExitCode(nil))
+	assert.Equal(t, errs.ExitUsage, errs.ExitCode(errs.New(errs.Usage, "bad")))
+	assert.Equal(t, errs.ExitInternal, errs.ExitCode(errors.New("boom")))
+	assert.Equal(t, 143, errs.ExitCode(fmt.Errorf("run: %w", &toolkit.SignalError{Signal: syscall.SIGTERM})))
+	assert.Equal(t, 7, errs.ExitCode(&toolkit.ExitError{Code: 7}))
+	assert.Equal(t, errs.ExitConflict, errs.ExitCode(errs.WithKind(&toolkit.ExitError{Code: 7}, errs.Conflict)))
+}
+
+func TestError_MessageAndHint(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("sync: %w", errs.WithHint(
+		errs.Wrap(fs.ErrNotExist, errs.NotFound, "no profile named \"work\""),
+		"List profiles with `tool profile ls`."))
+	assert.Equal(t, "sync: no profile named \"work\": file does not exist", err.Error())
+	assert.Equal(t, "List profiles with `tool profile ls`.", errs.Hint(err))
+	assert.Empty(t, errs.Hint(errors.New("plain")))
+	assert.Nil(t, errs.Wrap(nil, errs.Usage, "x"))
+	assert.Nil(t, errs.WithHint(nil, "x"))
+}
+
+func TestRender_WritesMessageAndLogs(t *testing.T) {
+	t.Parallel()
+
+	lg, th := mylog.NewTestLogger(t, slog.LevelDebug)
+	sb := tu.NewSandbox(t, nil)
+	rt := sb.Runtime()
+	require.NoError(t, rt.SetLogger(lg))
+	stderr := captureErr(t, rt)
+
+	err := fmt.Errorf("open config: %w", fs.ErrNotExist)
+	code := errs.Render(rt, err, errs.WithDefaultHint(errs.NotFound, "Run `tool init`."))
+
+	assert.Equal(t, errs.ExitNotFound, code)
+	assert.Equal(t, "error: open config: file does not exist\nhint: Run `tool init`.\n", stderr.String())
+	entries := mylog.FindEntries(th, func(e mylog.LoggedEntry) bool { return e.Msg == "command failed" })
+	require.Len(t, entries, 1)
+	assert.Equal(t, "not-found", entries[0].Attrs["kind"])
+	assert.Equal(t, int64(errs.ExitNotFound), entries[0].Attrs["exit_code"])
+	assert.Contains(t, entries[0].Attrs["cause"], "file does not exist")
+}
+
+func TestRender_ShowsMessageWithoutCause(t *testing.T) {
+	t.Parallel()
+
+	lg, th := mylog.NewTestLogger(t, slog.LevelDebug)
+	sb := tu.NewSandbox(t, nil)
+	rt := sb.Runtime()
+	require.NoError(t, rt.SetLogger(lg))
+	stderr := captureErr(t, rt)
+
+	err := fmt.Errorf("sync: %w", errs.WithHint(
+		errs.Wrap(fs.ErrNotExist, errs.NotFound, "no profile named \"work\""),
+		"List profiles with `tool profile ls`."))
+	errs.Render(rt, err)
+
+	assert.Equal(t,
+		"error: no profile named \"work\"\nhint: List profiles with `tool profile ls`.\n",
+		stderr.String())
+	assert.NotContains(t, stderr.String(), "file does not exist")
+	entries := mylog.FindEntries(th, func(e mylog.LoggedEntry) bool { return e.Msg == "command failed" })
+	require.Len(t, entries, 1)
+	assert.Equal(t, err.Error(), entries[0].Attrs["error"])
+}
+
+func TestRender_ColorsOnTerminal(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil, tu.WithTTY(80, 24), tu.WithColor(toolkit.ColorBasic))
+	stderr := captureErr(t, sb.Runtime())
+
+	errs.Render(sb.Runtime(), errs.New(errs.Usage, "bad flag"))
+	assert.Contains(t, stderr.String(), "\x1b[")
+	assert.Equal(t, "error: bad flag\n", toolkit.StripANSI(stderr.String()))
+}
+
+func TestRender_Nil(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	stderr := captureErr(t, sb.Runtime())
+	assert.Equal(t, 0, errs.Render(sb.Runtime(), nil))
+	assert.Empty(t, stderr.String())
+}
+
+// captureErr replaces the runtime stderr with a buffer, keeping the TTY flag.
+func captureErr(t *testing.T, rt *toolkit.Runtime) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	stream := *rt.Stream()
+	stream.Err = buf
+	require.NoError(t, rt.SetStream(&stream))
+	return buf
+}
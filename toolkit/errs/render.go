@@ -0,0 +1,115 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// RenderOption configures [Render].
+type RenderOption func(*renderer)
+
+type renderer struct {
+	hints map[Kind]string
+}
+
+// WithDefaultHint sets the hint shown for errors of kind k that carry no
+// hint of their own, such as "Run `tool --help` for usage." for [Usage].
+func WithDefaultHint(k Kind, hint string) RenderOption {
+	return func(r *renderer) {
+		r.hints[k] = hint
+	}
+}
+
+var (
+	errorStyle = toolkit.Style{}.Foreground(toolkit.Red).Bold()
+	hintStyle  = toolkit.Style{}.Foreground(toolkit.Cyan)
+)
+
+// Render reports err and returns its exit code. It writes the message and
+// any hint to the runtime Stream.Err, colored when the terminal allows, and
+// logs the full error with its kind and exit code to the runtime logger.
+// Nothing is written for a nil error.
+//
+// The message is the Msg of the outermost [*Error] in the chain that has one,
+// so wrapped causes stay in the log rather than on the terminal. Errors
+// without such a message are shown in full.
+//
+//	error: no profile named "work"
+//	hint: List profiles with `tool profile ls`.
+func Render(rt *toolkit.Runtime, err error, opts ...RenderOption) int {
+	if err == nil {
+		return ExitOK
+	}
+	r := &renderer{hints: map[Kind]string{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	kind := KindOf(err)
+	code := ExitCode(err)
+	hint := Hint(err)
+	if hint == "" {
+		hint = r.hints[kind]
+	}
+
+	level := slog.LevelError
+	if kind == Interrupted {
+		level = slog.LevelInfo
+	}
+	rt.Logger().LogAttrs(context.Background(), level, "command failed",
+		slog.String("kind", kind.String()),
+		slog.Int("exit_code", code),
+		slog.String("error", err.Error()),
+		slog.String("cause", cause(err)),
+	)
+
+	w := toolkit.OrDefaultStream(rt.Stream()).Err
+	if w == nil {
+		return code
+	}
+	term := rt.Terminal()
+	label := "error"
+	if kind == Interrupted {
+		label = "interrupted"
+	}
+	fmt.Fprintf(w, "%s: %s\n", errorStyle.Render(term, label), message(err))
+	if hint != "" {
+		fmt.Fprintf(w, "%s: %s\n", hintStyle.Render(term, "hint"), hint)
+	}
+	return code
+}
+
+// message returns the Msg of the outermost *Error in err's chain that has
+// one, or err.Error() if none does.
+func message(err error) string {
+	for next := err; ; {
+		var e *Error
+		if !errors.As(next, &e) {
+			return err.Error()
+		}
+		if e.Msg != "" {
+			return e.Msg
+		}
+		if e.Err == nil {
+			return err.Error()
+		}
+		next = e.Err
+	}
+}
+
+// cause describes the innermost error in the chain with its type, for logs.
+func cause(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T: %v", err, err)
+		}
+		err = next
+	}
+}
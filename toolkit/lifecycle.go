@@ -0,0 +1,143 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// closeScope holds the cleanup hooks for a runtime and the scopes of its
+// clones. A clone's scope is tracked by its parent only once it has hooks or
+// tracked clones of its own, so clones that never register a hook can be
+// dropped without closing them.
+type closeScope struct {
+	mu       sync.Mutex
+	parent   *closeScope
+	hooks    []func(ctx context.Context) error
+	children []*closeScope
+	// tracked reports whether the scope was added to parent.children.
+	tracked bool
+	closed  bool
+}
+
+func newCloseScope(parent *closeScope) *closeScope {
+	s := &closeScope{parent: parent}
+	if parent != nil {
+		parent.mu.Lock()
+		// A clone of a closed runtime starts closed as well.
+		s.closed = parent.closed
+		parent.mu.Unlock()
+	}
+	return s
+}
+
+// track adds child to the scope's children, tracking the scope with its own
+// parent first if needed. It reports false when the scope or an ancestor is
+// already closed, in which case the caller is left untracked.
+func (s *closeScope) track(child *closeScope) bool {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return false
+	}
+	s.children = append(s.children, child)
+	register := !s.tracked
+	s.tracked = true
+	s.mu.Unlock()
+
+	if register && s.parent != nil && !s.parent.track(s) {
+		// An ancestor closed before this scope was tracked; close it now, as
+		// if it had been cloned from a closed runtime.
+		_ = s.close(context.Background())
+		return false
+	}
+	return true
+}
+
+// OnClose registers fn to run when the runtime is closed. Hooks run in LIFO
+// order, so resources are released in the reverse order they were acquired.
+//
+// If the runtime is already closed, fn runs immediately and any error is
+// logged.
+func (rt *Runtime) OnClose(fn func(ctx context.Context) error) {
+	if fn == nil {
+		return
+	}
+	s := rt.closeScope()
+	s.mu.Lock()
+	if !s.closed {
+		s.hooks = append(s.hooks, fn)
+		register := !s.tracked
+		s.tracked = true
+		s.mu.Unlock()
+		if !register || s.parent == nil || s.parent.track(s) {
+			return
+		}
+		// An ancestor is closed, so close this scope and run fn with it.
+		if err := s.close(context.Background()); err != nil {
+			rt.Logger().Warn("close hook registered after close failed", "error", err)
+		}
+		return
+	}
+	s.mu.Unlock()
+	if err := fn(context.Background()); err != nil {
+		rt.Logger().Warn("close hook registered after close failed", "error", err)
+	}
+}
+
+// Close runs the registered close hooks and returns their errors joined.
+//
+// Each clone made with [Runtime.Clone] is a child scope: closing a runtime
+// first closes any clones that are still open and have hooks, in reverse
+// order of their first OnClose, then runs its own hooks. Closing a clone runs
+// only its hooks and those of its own clones. Close is idempotent; later
+// calls return nil.
+func (rt *Runtime) Close(ctx context.Context) error {
+	if rt == nil {
+		return nil
+	}
+	return rt.closeScope().close(ctx)
+}
+
+// closeScope returns the runtime scope, creating it for runtimes built
+// without a constructor.
+func (rt *Runtime) closeScope() *closeScope {
+	if rt.scope == nil {
+		rt.scope = newCloseScope(nil)
+	}
+	return rt.scope
+}
+
+func (s *closeScope) close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	hooks := s.hooks
+	children := s.children
+	s.hooks = nil
+	s.children = nil
+	s.mu.Unlock()
+
+	if p := s.parent; p != nil {
+		p.mu.Lock()
+		p.children = slices.DeleteFunc(p.children, func(c *closeScope) bool { return c == s })
+		p.mu.Unlock()
+	}
+
+	var errs []error
+	for _, child := range slices.Backward(children) {
+		if err := child.close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, fn := range slices.Backward(hooks) {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+package toolkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime_CloseRunsHooksLIFO(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "", "")
+	require.NoError(t, err)
+
+	var order []string
+	errA := errors.New("a failed")
+	errC := errors.New("c failed")
+	rt.OnClose(func(ctx context.Context) error { order = append(order, "a"); return errA })
+	rt.OnClose(func(ctx context.Context) error { order = append(order, "b"); return nil })
+	rt.OnClose(func(ctx context.Context) error { order = append(order, "c"); return errC })
+
+	err = rt.Close(t.Context())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errC)
+	assert.Equal(t, []string{"c", "b", "a"}, order)
+
+	require.NoError(t, rt.Close(t.Context()), "close is idempotent")
+	assert.Len(t, order, 3)
+
+	rt.OnClose(func(ctx context.Context) error { order = append(order, "late"); return nil })
+	assert.Equal(t, []string{"c", "b", "a", "late"}, order, "hooks added after close run immediately")
+}
+
+func TestRuntime_CloseClonesAsChildScopes(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "", "")
+	require.NoError(t, err)
+
+	var order []string
+	hook := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	rt.OnClose(hook("parent"))
+	first := rt.Clone()
+	first.OnClose(hook("first"))
+	second := rt.Clone()
+	second.OnClose(hook("second"))
+	grandchild := second.Clone()
+	grandchild.OnClose(hook("grandchild"))
+
+	require.NoError(t, first.Close(t.Context()))
+	assert.Equal(t, []string{"first"}, order, "closing a clone runs only its hooks")
+
+	require.NoError(t, rt.Close(t.Context()))
+	assert.Equal(t, []string{"first", "grandchild", "second", "parent"}, order)
+
+	// Clones of a closed runtime are closed too.
+	late := rt.Clone()
+	late.OnClose(hook("late"))
+	assert.Equal(t, "late", order[len(order)-1])
+}
+
+func TestRuntime_CloseReachesClonesThatRegisterLate(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "", "")
+	require.NoError(t, err)
+
+	var order []string
+	hook := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	// Clones without hooks are not tracked, but a hook registered deep in an
+	// untracked chain still runs when the root closes.
+	for range 100 {
+		_ = rt.Fork()
+	}
+	middle := rt.Clone()
+	leaf := middle.Fork()
+	leaf.OnClose(hook("leaf"))
+
+	require.NoError(t, rt.Close(t.Context()))
+	assert.Equal(t, []string{"leaf"}, order)
+
+	// An untracked clone made before a close that registers a hook afterwards
+	// runs it immediately, like a clone of a closed runtime.
+	root, err := toolkit.NewTestRuntime(t.TempDir(), "", "")
+	require.NoError(t, err)
+	early := root.Clone().Clone()
+	require.NoError(t, root.Close(t.Context()))
+	early.OnClose(hook("early"))
+	assert.Equal(t, []string{"leaf", "early"}, order)
+}
@@ -0,0 +1,87 @@
+package toolkit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+)
+
+// LocationFromEnv returns the time zone named by the TZ variable in env,
+// following the same rules as the Go runtime: an unset TZ means the host
+// zone ([time.Local]), an empty TZ or "UTC" means UTC, and a leading ":" is
+// ignored. An unknown zone name is reported as an error.
+func LocationFromEnv(env Env) (*time.Location, error) {
+	if env == nil || !env.Has("TZ") {
+		return time.Local, nil
+	}
+	name := strings.TrimPrefix(env.Get("TZ"), ":")
+	if name == "" || name == "UTC" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TZ %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// WithRuntimeLocation sets the time zone used by [Runtime.Now]. Without it
+// the runtime derives the zone from the env TZ variable.
+func WithRuntimeLocation(loc *time.Location) RuntimeOption {
+	return func(rt *Runtime) error {
+		if loc == nil {
+			return fmt.Errorf("runtime location cannot be nil")
+		}
+		rt.loc = loc
+		return nil
+	}
+}
+
+// Location returns the runtime time zone.
+func (rt *Runtime) Location() *time.Location {
+	if rt == nil || rt.loc == nil {
+		return time.Local
+	}
+	return rt.loc
+}
+
+// SetLocation updates the runtime time zone. A [clock.TestClock] runtime
+// clock is switched to loc too.
+func (rt *Runtime) SetLocation(loc *time.Location) error {
+	if loc == nil {
+		return fmt.Errorf("runtime location cannot be nil")
+	}
+	rt.loc = loc
+	if tc, ok := rt.clock.(*clock.TestClock); ok {
+		tc.SetLocation(loc)
+	}
+	return nil
+}
+
+// Now returns the runtime clock time in the runtime time zone. Format times
+// from Now rather than from the clock directly so output does not depend on
+// the host TZ. Through Now the Runtime is itself a [clock.Clock], so it can
+// be passed to helpers that read calendar days from the clock.
+func (rt *Runtime) Now() time.Time {
+	return rt.clock.Now().In(rt.Location())
+}
+
+var _ clock.Clock = (*Runtime)(nil)
+
+// normalizeLocation derives the time zone from env TZ when no location was
+// set, falling back to UTC for an unknown zone as the Go runtime does, and
+// moves a TestClock without a location of its own to it.
+func (rt *Runtime) normalizeLocation() {
+	if rt.loc == nil {
+		loc, err := LocationFromEnv(rt.env)
+		if err != nil {
+			loc = time.UTC
+		}
+		rt.loc = loc
+	}
+	if tc, ok := rt.clock.(*clock.TestClock); ok && tc.Location() == nil {
+		tc.SetLocation(rt.loc)
+	}
+}
@@ -0,0 +1,129 @@
+package toolkit_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocationFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tz      string
+		unset   bool
+		want    string
+		wantErr bool
+	}{
+		{name: "unset", unset: true, want: "Local"},
+		{name: "empty", tz: "", want: "UTC"},
+		{name: "utc", tz: "UTC", want: "UTC"},
+		{name: "zone", tz: "America/New_York", want: "America/New_York"},
+		{name: "colon prefix", tz: ":Europe/Paris", want: "Europe/Paris"},
+		{name: "unknown", tz: "Nowhere/Special", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := toolkit.NewTestEnv(t.TempDir(), "/home/testuser", "testuser")
+			if !tt.unset {
+				require.NoError(t, env.Set("TZ", tt.tz))
+			}
+			loc, err := toolkit.LocationFromEnv(env)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, loc.String())
+		})
+	}
+}
+
+func TestRuntime_Location(t *testing.T) {
+	t.Parallel()
+
+	seed := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+
+	t.Run("test runtime defaults to UTC", func(t *testing.T) {
+		t.Parallel()
+		tc := clock.NewTestClock(seed)
+		rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser",
+			toolkit.WithRuntimeClock(tc))
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, rt.Location())
+		assert.Equal(t, "2025-07-01T16:00:00Z", rt.Now().Format(time.RFC3339))
+		assert.Equal(t, time.UTC, tc.Now().Location())
+	})
+
+	t.Run("derived from env TZ", func(t *testing.T) {
+		t.Parallel()
+		jail := t.TempDir()
+		env := toolkit.NewTestEnv(jail, "/home/testuser", "testuser")
+		require.NoError(t, env.Set("TZ", "America/New_York"))
+		rt, err := toolkit.NewRuntime(
+			toolkit.WithRuntimeEnv(env),
+			toolkit.WithRuntimeJail(jail),
+			toolkit.WithRuntimeClock(clock.NewTestClock(seed)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "America/New_York", rt.Location().String())
+		assert.Equal(t, "2025-07-01T12:00:00-04:00", rt.Now().Format(time.RFC3339))
+		assert.Equal(t, "America/New_York", rt.Describe().Location)
+	})
+
+	t.Run("option and setter", func(t *testing.T) {
+		t.Parallel()
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		tc := clock.NewTestClock(seed)
+		rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser",
+			toolkit.WithRuntimeClock(tc),
+			toolkit.WithRuntimeLocation(tokyo))
+		require.NoError(t, err)
+		assert.Equal(t, "2025-07-02T01:00:00+09:00", rt.Now().Format(time.RFC3339))
+		assert.Equal(t, tokyo, tc.Location())
+
+		require.NoError(t, rt.SetLocation(time.UTC))
+		assert.Equal(t, time.UTC, tc.Location())
+		assert.Error(t, rt.SetLocation(nil))
+
+		_, err = toolkit.NewRuntime(toolkit.WithRuntimeLocation(nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("set clock adopts runtime zone", func(t *testing.T) {
+		t.Parallel()
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser",
+			toolkit.WithRuntimeLocation(tokyo))
+		require.NoError(t, err)
+
+		tc := clock.NewTestClock(seed)
+		require.NoError(t, rt.SetClock(tc))
+		assert.Equal(t, tokyo, tc.Location())
+		assert.Equal(t, "2025-07-02T01:00:00+09:00", tc.Now().Format(time.RFC3339))
+	})
+
+	t.Run("set clock keeps explicit zone", func(t *testing.T) {
+		t.Parallel()
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/testuser", "testuser",
+			toolkit.WithRuntimeLocation(tokyo))
+		require.NoError(t, err)
+
+		tc := clock.NewTestClock(seed)
+		tc.SetLocation(time.UTC)
+		require.NoError(t, rt.SetClock(tc))
+		assert.Equal(t, time.UTC, tc.Location())
+		assert.Equal(t, "2025-07-02T01:00:00+09:00", rt.Now().Format(time.RFC3339))
+	})
+}
@@ -9,6 +9,53 @@ import (
 	"github.com/jlrickert/cli-toolkit/mylog"
 )
 
+// Log attribute keys used by [Runtime.LogAttrs].
+const (
+	LogKeyCommand    = "command"
+	LogKeyInvocation = "invocation_id"
+)
+
+// CommandAttr returns the log attribute naming the running command.
+func CommandAttr(name string) slog.Attr {
+	return slog.String(LogKeyCommand, name)
+}
+
+// InvocationAttr returns the log attribute identifying a single invocation.
+func InvocationAttr(id string) slog.Attr {
+	return slog.String(LogKeyInvocation, id)
+}
+
+// LogAttrs returns the attributes identifying this invocation: the command
+// name and the invocation ID from [ProcessInfo.UID]. It returns nil when the
+// runtime has no process info. The attributes are typed, so they can be
+// passed to slog methods without alternating key/value arguments:
+//
+//	lg := rt.Logger().With(toolkit.AnyAttrs(rt.LogAttrs())...)
+func (rt *Runtime) LogAttrs() []slog.Attr {
+	p := rt.Process()
+	if p == nil {
+		return nil
+	}
+	var attrs []slog.Attr
+	if p.Name != "" {
+		attrs = append(attrs, CommandAttr(p.Name))
+	}
+	if p.UID != "" {
+		attrs = append(attrs, InvocationAttr(p.UID))
+	}
+	return attrs
+}
+
+// AnyAttrs converts attrs for slog methods that take ...any, such as
+// slog.Logger.With, keeping each argument a typed slog.Attr.
+func AnyAttrs(attrs []slog.Attr) []any {
+	args := make([]any, len(attrs))
+	for i, a := range attrs {
+		args[i] = a
+	}
+	return args
+}
+
 func getTookitLogger(rt *Runtime) *slog.Logger {
 	var lg *slog.Logger
 	if rt != nil {
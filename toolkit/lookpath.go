@@ -0,0 +1,105 @@
+package toolkit
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// defaultPathExt mirrors the Windows default when PATHEXT is unset.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// LookPath searches the runtime PATH for an executable named name and returns
+// its runtime path. Unlike [exec.LookPath] it reads PATH from the runtime Env
+// and checks candidates through the runtime FileSystem, so a jailed runtime
+// only sees executables inside the jail.
+//
+// Names containing a path separator are checked directly, relative to the
+// runtime working directory. On Unix-like systems a candidate must be a
+// regular file with an executable bit set. When PATHEXT is set in the runtime
+// env (or on Windows, where it defaults to .COM;.EXE;.BAT;.CMD) each suffix is
+// also tried, and the executable bit is not required.
+//
+// A failed lookup returns an [*exec.Error] wrapping [exec.ErrNotFound].
+func (rt *Runtime) LookPath(name string) (string, error) {
+	if err := rt.Validate(); err != nil {
+		return "", err
+	}
+	exts := rt.pathExts()
+
+	if strings.ContainsAny(name, `/\`) {
+		if p, ok := rt.findExecutable(name, exts); ok {
+			return p, nil
+		}
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+
+	for _, dir := range filepath.SplitList(rt.Get("PATH")) {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		if p, ok := rt.findExecutable(filepath.Join(dir, name), exts); ok {
+			return p, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// pathExts returns the executable suffixes to try, or nil when suffix
+// matching is not in effect.
+func (rt *Runtime) pathExts() []string {
+	raw := rt.Get("PATHEXT")
+	if raw == "" {
+		if runtime.GOOS != "windows" {
+			return nil
+		}
+		raw = defaultPathExt
+	}
+	var exts []string
+	for ext := range strings.SplitSeq(raw, ";") {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	return exts
+}
+
+// findExecutable returns the resolved runtime path for candidate, trying each
+// suffix in exts when candidate does not already carry one.
+func (rt *Runtime) findExecutable(candidate string, exts []string) (string, bool) {
+	if len(exts) == 0 {
+		return rt.checkExecutable(candidate, true)
+	}
+	for _, ext := range exts {
+		if n := len(candidate) - len(ext); n > 0 && strings.EqualFold(candidate[n:], ext) {
+			return rt.checkExecutable(candidate, false)
+		}
+	}
+	for _, ext := range exts {
+		if p, ok := rt.checkExecutable(candidate+ext, false); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func (rt *Runtime) checkExecutable(candidate string, needExecBit bool) (string, bool) {
+	path, err := rt.AbsPath(candidate)
+	if err != nil {
+		return "", false
+	}
+	fi, err := rt.Stat(path, true)
+	if err != nil || !fi.Mode().IsRegular() {
+		return "", false
+	}
+	if needExecBit && fi.Mode().Perm()&0o111 == 0 {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,91 @@
+package toolkit_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLookPathRuntime(t *testing.T) *toolkit.Runtime {
+	t.Helper()
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/alice", "alice")
+	require.NoError(t, err)
+	require.NoError(t, rt.Mkdir("/bin", 0o755, true))
+	require.NoError(t, rt.Mkdir("/usr/bin", 0o755, true))
+	return rt
+}
+
+func writeExecutable(t *testing.T, rt *toolkit.Runtime, path string, mode os.FileMode) {
+	t.Helper()
+	require.NoError(t, rt.WriteFile(path, []byte("#!/bin/sh\n"), 0o644))
+	require.NoError(t, rt.Chmod(path, mode))
+}
+
+func TestRuntime_LookPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on windows")
+	}
+	t.Parallel()
+
+	rt := newLookPathRuntime(t)
+	writeExecutable(t, rt, "/bin/tool", 0o644)
+	writeExecutable(t, rt, "/usr/bin/tool", 0o755)
+	writeExecutable(t, rt, "/bin/other", 0o755)
+	require.NoError(t, rt.Mkdir("/bin/dir", 0o755, true))
+	require.NoError(t, rt.Set("PATH", "/bin"+string(filepath.ListSeparator)+"/usr/bin"))
+
+	got, err := rt.LookPath("tool")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/usr/bin/tool"), got, "non-executable earlier entry is skipped")
+
+	got, err = rt.LookPath("other")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/bin/other"), got)
+
+	_, err = rt.LookPath("dir")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	_, err = rt.LookPath("missing")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	got, err = rt.LookPath("/bin/other")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/bin/other"), got)
+
+	_, err = rt.LookPath("./other")
+	require.ErrorIs(t, err, exec.ErrNotFound, "relative names resolve against the working directory")
+}
+
+func TestRuntime_LookPath_IgnoresHostPath(t *testing.T) {
+	t.Parallel()
+
+	rt := newLookPathRuntime(t)
+	require.NoError(t, rt.Set("PATH", "/usr/bin"))
+
+	_, err := rt.LookPath("sh")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+}
+
+func TestRuntime_LookPath_PathExt(t *testing.T) {
+	t.Parallel()
+
+	rt := newLookPathRuntime(t)
+	writeExecutable(t, rt, "/bin/tool.CMD", 0o644)
+	writeExecutable(t, rt, "/bin/app.exe", 0o644)
+	require.NoError(t, rt.Set("PATH", "/bin"))
+	require.NoError(t, rt.Set("PATHEXT", ".EXE;.CMD"))
+
+	got, err := rt.LookPath("tool")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/bin/tool.CMD"), got)
+
+	got, err = rt.LookPath("app.exe")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/bin/app.exe"), got)
+}
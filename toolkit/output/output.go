@@ -0,0 +1,267 @@
+// Package output renders Go values in the formats CLIs commonly expose
+// through an --output flag.
+//
+// A format is selected by name with [Parse] or [Render]: "table" (the
+// default), "json", "ndjson", "yaml", "csv" or "template=TEXT", where TEXT is
+// a [text/template]. Output is written to Runtime.Stream().Out.
+//
+// Table and CSV output need rows. A slice or array renders one row per
+// element; any other value renders as a single row. Structs contribute their
+// exported fields, maps their sorted keys, and other values a single VALUE
+// column. Column names come from an `output` struct tag, then the `json` tag,
+// then the field name; a tag of "-" hides the field. JSON and YAML follow
+// the usual `json` tags so every structured format agrees on field names.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"gopkg.in/yaml.v3"
+)
+
+// Format names an output format.
+type Format string
+
+const (
+	Table    Format = "table"
+	JSON     Format = "json"
+	NDJSON   Format = "ndjson"
+	YAML     Format = "yaml"
+	CSV      Format = "csv"
+	Template Format = "template"
+)
+
+// ErrUnknownFormat is returned by [Parse] for unsupported format names.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Names returns the accepted format names, suitable for flag help text.
+func Names() []string {
+	return []string{
+		string(Table), string(JSON), string(NDJSON),
+		string(YAML), string(CSV), string(Template) + "=TEXT",
+	}
+}
+
+// Option configures a Renderer.
+type Option func(*Renderer)
+
+// WithColumns selects and orders the columns used by table and CSV output.
+// Names are matched case-insensitively.
+func WithColumns(names ...string) Option {
+	return func(r *Renderer) {
+		r.columns = names
+	}
+}
+
+// WithNoHeaders omits the header row from table and CSV output.
+func WithNoHeaders() Option {
+	return func(r *Renderer) {
+		r.noHeaders = true
+	}
+}
+
+// WithTemplateFuncs adds functions available to template output.
+func WithTemplateFuncs(funcs template.FuncMap) Option {
+	return func(r *Renderer) {
+		for k, v := range funcs {
+			r.funcs[k] = v
+		}
+	}
+}
+
+// Renderer writes values in a single format.
+type Renderer struct {
+	format    Format
+	text      string
+	columns   []string
+	noHeaders bool
+	funcs     template.FuncMap
+	tmpl      *template.Template
+}
+
+// Parse returns a Renderer for spec. An empty spec selects [Table]; "jsonl"
+// and "yml" are accepted as aliases. Template output takes its text after an
+// equals sign, as in "template={{.Name}}\n".
+func Parse(spec string, opts ...Option) (*Renderer, error) {
+	name, text, hasText := strings.Cut(spec, "=")
+	r := &Renderer{
+		funcs: template.FuncMap{"json": templateJSON},
+	}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "table":
+		r.format = Table
+	case "json":
+		r.format = JSON
+	case "ndjson", "jsonl":
+		r.format = NDJSON
+	case "yaml", "yml":
+		r.format = YAML
+	case "csv":
+		r.format = CSV
+	case "template", "go-template":
+		r.format = Template
+		if !hasText || text == "" {
+			return nil, fmt.Errorf("output: %q: template text is required, as in template={{.Name}}", spec)
+		}
+	default:
+		return nil, fmt.Errorf("output: %q: %w (want one of %s)",
+			spec, ErrUnknownFormat, strings.Join(Names(), ", "))
+	}
+	if hasText && r.format != Template {
+		return nil, fmt.Errorf("output: %q: only template output takes an argument", spec)
+	}
+	r.text = text
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	if r.format == Template {
+		t, err := template.New("output").Funcs(r.funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("output: parse template: %w", err)
+		}
+		r.tmpl = t
+	}
+	return r, nil
+}
+
+// Render parses spec and renders v to the runtime stdout.
+func Render(rt *toolkit.Runtime, spec string, v any, opts ...Option) error {
+	r, err := Parse(spec, opts...)
+	if err != nil {
+		return err
+	}
+	return r.Render(rt, v)
+}
+
+// Format returns the selected format.
+func (r *Renderer) Format() Format { return r.format }
+
+// Render writes v to the runtime stdout. Table output is fitted to the
+// terminal width when stdout is a terminal.
+func (r *Renderer) Render(rt *toolkit.Runtime, v any) error {
+	s := toolkit.OrDefaultStream(rt.Stream())
+	w := s.Out
+	if w == nil {
+		w = io.Discard
+	}
+	term := rt.Terminal()
+	width := 0
+	if term.IsTTY {
+		width = term.Width
+	}
+	if err := r.render(w, v, width, term.Unicode); err != nil {
+		return fmt.Errorf("output: render %s: %w", r.format, err)
+	}
+	return nil
+}
+
+// Write renders v to w without any terminal fitting.
+func (r *Renderer) Write(w io.Writer, v any) error {
+	if err := r.render(w, v, 0, false); err != nil {
+		return fmt.Errorf("output: render %s: %w", r.format, err)
+	}
+	return nil
+}
+
+func (r *Renderer) render(w io.Writer, v any, width int, unicode bool) error {
+	switch r.format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		items, ok := elements(v)
+		if !ok {
+			return enc.Encode(v)
+		}
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case YAML:
+		return writeYAML(w, v)
+	case CSV:
+		tab, err := r.table(v)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(w)
+		if !r.noHeaders {
+			if err := cw.Write(tab.columns); err != nil {
+				return err
+			}
+		}
+		if err := cw.WriteAll(tab.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case Template:
+		return r.tmpl.Execute(w, v)
+	default:
+		tab, err := r.table(v)
+		if err != nil {
+			return err
+		}
+		return tab.write(w, width, unicode, !r.noHeaders)
+	}
+}
+
+// writeYAML encodes v through its JSON form so that `json` tags and struct
+// field order carry over to YAML.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// clearStyle drops the flow and quoting styles inherited from JSON so the
+// result reads as block YAML.
+func clearStyle(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		n.Style = 0
+	} else {
+		n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	}
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
+
+func templateJSON(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
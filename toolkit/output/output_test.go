@@ -0,0 +1,183 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type repo struct {
+	Name    string    `json:"name"`
+	Stars   int       `json:"stars"`
+	Private bool      `json:"private" output:"-"`
+	Topics  []string  `json:"topics"`
+	Updated time.Time `json:"updated"`
+}
+
+var repos = []repo{
+	{Name: "cli-toolkit", Stars: 42, Topics: []string{"go", "cli"}, Updated: time.Date(2025, 10, 15, 12, 30, 0, 0, time.UTC)},
+	{Name: "dotfiles", Stars: 7, Private: true, Updated: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+}
+
+func render(t *testing.T, spec string, v any, opts ...output.Option) string {
+	t.Helper()
+	sb := tu.NewSandbox(t, nil)
+	out := &bytes.Buffer{}
+	require.NoError(t, sb.Runtime().SetStream(&toolkit.Stream{Out: out, Err: &bytes.Buffer{}}))
+	require.NoError(t, output.Render(sb.Runtime(), spec, v, opts...))
+	return out.String()
+}
+
+func TestRender_Table(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"NAME          STARS   TOPICS   UPDATED\n"+
+			"cli-toolkit   42      go,cli   2025-10-15T12:30:00Z\n"+
+			"dotfiles      7                2024-01-02T03:04:05Z\n",
+		render(t, "", repos))
+
+	assert.Equal(t,
+		"7   dotfiles\n",
+		render(t, "table", repos[1], output.WithColumns("STARS", "name"), output.WithNoHeaders()))
+
+	assert.Equal(t,
+		"A   B\n1   x\n",
+		render(t, "table", map[string]any{"b": "x", "a": 1}))
+
+	assert.Equal(t, "VALUE\none\ntwo\n", render(t, "table", []string{"one", "two"}))
+}
+
+func TestRender_TableEscapesControlCharacters(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]string{{"name": "a\tb\nc", "id": "1"}}
+	assert.Equal(t,
+		"ID   NAME\n"+
+			"1    a\\tb\\nc\n",
+		render(t, "table", rows))
+	assert.Equal(t, "id,name\n1,\"a\tb\nc\"\n", render(t, "csv", rows), "csv keeps raw values")
+}
+
+func TestRender_TableAlignsWideCharacters(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]string{
+		{"name": "日本語", "id": "1"},
+		{"name": "abc", "id": "2"},
+	}
+	assert.Equal(t,
+		"NAME     ID\n"+
+			"日本語   1\n"+
+			"abc      2\n",
+		render(t, "table", rows, output.WithColumns("name", "id")))
+}
+
+func TestRender_TableNilAndEmptyMatch(t *testing.T) {
+	t.Parallel()
+
+	var nilRepo *repo
+	assert.Empty(t, render(t, "table", nil))
+	assert.Empty(t, render(t, "table", nilRepo))
+	assert.Empty(t, render(t, "table", []repo{}))
+	assert.Equal(t,
+		render(t, "table", []repo{}, output.WithColumns("name")),
+		render(t, "table", nil, output.WithColumns("name")))
+}
+
+func TestRender_TableFitsTerminal(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil, tu.WithTTY(40, 10))
+	out := &bytes.Buffer{}
+	require.NoError(t, sb.Runtime().SetStream(&toolkit.Stream{Out: out, IsTTY: true}))
+
+	rows := []map[string]string{{
+		"id":          "1",
+		"description": strings.Repeat("long text ", 10),
+	}}
+	require.NoError(t, output.Render(sb.Runtime(), "table", rows))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 39, "line %q", line)
+	}
+	assert.True(t, strings.HasSuffix(lines[1], "...   1"), "got %q", lines[1])
+}
+
+func TestRender_JSONAndNDJSON(t *testing.T) {
+	t.Parallel()
+
+	v := []map[string]any{{"a": 1}, {"a": "<b>"}}
+	assert.Equal(t, "[\n  {\n    \"a\": 1\n  },\n  {\n    \"a\": \"<b>\"\n  }\n]\n", render(t, "json", v))
+	assert.Equal(t, "{\"a\":1}\n{\"a\":\"<b>\"}\n", render(t, "ndjson", v))
+	assert.Equal(t, "{\"a\":1}\n", render(t, "jsonl", v[0]))
+}
+
+func TestRender_YAML(t *testing.T) {
+	t.Parallel()
+
+	got := render(t, "yaml", repos[:1])
+	assert.Equal(t,
+		"- name: cli-toolkit\n"+
+			"  stars: 42\n"+
+			"  private: false\n"+
+			"  topics:\n"+
+			"    - go\n"+
+			"    - cli\n"+
+			"  updated: \"2025-10-15T12:30:00Z\"\n",
+		got)
+
+	assert.Equal(t, "version: \"1.0\"\nyes: \"true\"\n",
+		render(t, "yml", map[string]string{"version": "1.0", "yes": "true"}))
+}
+
+func TestRender_CSV(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"name,topics\n"+
+			"cli-toolkit,\"go,cli\"\n"+
+			"dotfiles,\n",
+		render(t, "csv", repos, output.WithColumns("name", "topics")))
+}
+
+func TestRender_Template(t *testing.T) {
+	t.Parallel()
+
+	got := render(t, "template={{range .}}{{.Name}}={{.Stars}} {{json .Topics}}\n{{end}}", repos)
+	assert.Equal(t, "cli-toolkit=42 [\"go\",\"cli\"]\ndotfiles=7 null\n", got)
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := output.Parse("xml")
+	require.ErrorIs(t, err, output.ErrUnknownFormat)
+	assert.Contains(t, err.Error(), "table, json")
+
+	_, err = output.Parse("template")
+	require.Error(t, err)
+
+	_, err = output.Parse("json=x")
+	require.Error(t, err)
+
+	_, err = output.Parse("template={{.Name")
+	require.ErrorContains(t, err, "parse template")
+
+	sb := tu.NewSandbox(t, nil)
+	err = output.Render(sb.Runtime(), "table", repos, output.WithColumns("owner"))
+	require.ErrorContains(t, err, `output: render table: unknown column "owner"`)
+
+	r, err := output.Parse("YAML")
+	require.NoError(t, err)
+	assert.Equal(t, output.YAML, r.Format())
+}
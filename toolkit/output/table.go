@@ -0,0 +1,384 @@
+package output
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// tableData is a value flattened into named columns of string cells.
+type tableData struct {
+	columns []string
+	rows    [][]string
+}
+
+// elements returns the items of a slice or array value.
+func elements(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+// table flattens v into rows, honoring the configured columns.
+func (r *Renderer) table(v any) (*tableData, error) {
+	items, ok := elements(v)
+	if !ok && !isNil(v) {
+		items = []any{v}
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	records := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		names, rec := record(item)
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				columns = append(columns, n)
+			}
+		}
+		records = append(records, rec)
+	}
+
+	if len(r.columns) > 0 {
+		selected := make([]string, 0, len(r.columns))
+		for _, want := range r.columns {
+			i := slices.IndexFunc(columns, func(c string) bool { return strings.EqualFold(c, want) })
+			if i < 0 && len(columns) > 0 {
+				return nil, fmt.Errorf("unknown column %q (have %s)", want, strings.Join(columns, ", "))
+			}
+			if i < 0 {
+				selected = append(selected, want)
+				continue
+			}
+			selected = append(selected, columns[i])
+		}
+		columns = selected
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = rec[c]
+		}
+		rows = append(rows, row)
+	}
+	return &tableData{columns: columns, rows: rows}, nil
+}
+
+// isNil reports whether v is nil or a nil pointer. Like an empty slice, it
+// renders as a table without rows.
+func isNil(v any) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+	return !rv.IsValid()
+}
+
+// record returns the column names and cells for a single row.
+func record(v any) ([]string, map[string]string) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return []string{"VALUE"}, map[string]string{"VALUE": ""}
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.Struct && !isScalar(rv):
+		var names []string
+		rec := map[string]string{}
+		rt := rv.Type()
+		for i := range rt.NumField() {
+			f := rt.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := columnName(f)
+			if name == "" {
+				continue
+			}
+			names = append(names, name)
+			rec[name] = cell(rv.Field(i))
+		}
+		return names, rec
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		names := make([]string, 0, rv.Len())
+		rec := map[string]string{}
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			names = append(names, k)
+			rec[k] = cell(iter.Value())
+		}
+		slices.Sort(names)
+		return names, rec
+	default:
+		return []string{"VALUE"}, map[string]string{"VALUE": cell(rv)}
+	}
+}
+
+func columnName(f reflect.StructField) string {
+	for _, key := range []string{"output", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+)
+
+// isScalar reports whether a struct value renders as a single cell.
+func isScalar(rv reflect.Value) bool {
+	t := rv.Type()
+	return t == timeType || t.Implements(textMarshalerType) || t.Implements(stringerType)
+}
+
+func cell(rv reflect.Value) string {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	if rv.Type() == timeType {
+		t := rv.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	if rv.CanInterface() {
+		switch x := rv.Interface().(type) {
+		case encoding.TextMarshaler:
+			if b, err := x.MarshalText(); err == nil {
+				return string(b)
+			}
+		case fmt.Stringer:
+			return x.String()
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = cell(rv.Index(i))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// write prints the table with columns separated by three spaces. When width
+// is positive, the widest columns are truncated until the table fits. Control
+// characters in cells are escaped so every row stays on one line; CSV output
+// keeps the raw values.
+func (t *tableData) write(w io.Writer, width int, unicode, headers bool) error {
+	n := len(t.columns)
+	if n == 0 {
+		return nil
+	}
+	lines := make([][]string, 0, len(t.rows)+1)
+	if headers {
+		header := make([]string, n)
+		for i, c := range t.columns {
+			header[i] = escapeControl(strings.ToUpper(c))
+		}
+		lines = append(lines, header)
+	}
+	for _, row := range t.rows {
+		esc := make([]string, n)
+		for i, c := range row {
+			esc[i] = escapeControl(c)
+		}
+		lines = append(lines, esc)
+	}
+
+	widths := make([]int, n)
+	for _, row := range lines {
+		for i, c := range row {
+			widths[i] = max(widths[i], displayWidth(c))
+		}
+	}
+	if width > 0 {
+		fitWidths(widths, width-1, len(tableSep))
+	}
+
+	var b strings.Builder
+	for _, row := range lines {
+		for i, c := range row {
+			c = truncate(c, widths[i], unicode)
+			b.WriteString(c)
+			if i < n-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(c)))
+				b.WriteString(tableSep)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const (
+	tableSep      = "   "
+	minTableWidth = 3
+)
+
+// fitWidths shrinks the widest columns one cell at a time until the row fits
+// in limit or every column is at its minimum.
+func fitWidths(widths []int, limit, sep int) {
+	total := sep * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > limit {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minTableWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate shortens s to at most width terminal columns, marking the cut
+// with an ellipsis.
+func truncate(s string, width int, unicode bool) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	mark := "..."
+	if unicode {
+		mark = "…"
+	}
+	if width <= displayWidth(mark) {
+		mark = ""
+	}
+	limit := width - displayWidth(mark)
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > limit {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + mark
+}
+
+// escapeControl replaces control characters with Go escapes such as \t and
+// \n so a cell cannot break the table layout.
+func escapeControl(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// displayWidth returns the number of terminal columns s occupies.
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// runeWidth returns the terminal columns taken by r: 0 for combining and
+// zero-width characters, 2 for East Asian wide and fullwidth characters and
+// most emoji, and 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200B || r == 0x200C || r == 0x200D || r == 0xFEFF,
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// wideRanges lists the main East Asian Wide and Fullwidth blocks, sorted.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1F64F}, // Pictographs, emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x20000, 0x2FFFD}, // CJK extensions B-F
+	{0x30000, 0x3FFFD}, // CJK extension G
+}
@@ -0,0 +1,203 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultPager is the pager command used when $PAGER is not set.
+var DefaultPager = "less -FRX"
+
+// PagerCommand returns the argv for the user's pager. It uses $PAGER, falling
+// back to [DefaultPager], and splits the value like [EditorCommand].
+func PagerCommand(rt *Runtime) ([]string, error) {
+	return resolveCommand(rt, DefaultPager, "PAGER")
+}
+
+// Pager routes the runtime stdout through the user's pager and returns a
+// function that flushes output, waits for the pager to exit and restores the
+// original stream. The returned function must be called once output is
+// complete:
+//
+//	done, err := toolkit.Pager(ctx, rt)
+//	if err != nil {
+//		return err
+//	}
+//	defer done()
+//
+// Output is buffered until it exceeds the terminal height, so short output is
+// written directly without starting the pager. When the height is unknown
+// the pager starts with the first write. Pager is a no-op when Stream.IsTTY
+// is false or $PAGER is "cat".
+//
+// Quitting the pager early is not an error: later writes are discarded. If
+// the pager cannot be started, output is written to the original stdout
+// instead.
+func Pager(ctx context.Context, rt *Runtime) (func() error, error) {
+	if err := rt.Validate(); err != nil {
+		return nil, err
+	}
+	noop := func() error { return nil }
+	orig := rt.Stream()
+	if !orig.IsTTY {
+		return noop, nil
+	}
+	argv, err := PagerCommand(rt)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Base(argv[0]) == "cat" && len(argv) == 1 {
+		return noop, nil
+	}
+
+	p := &pagerWriter{
+		ctx:    ctx,
+		rt:     rt,
+		argv:   argv,
+		out:    orig.Out,
+		height: rt.Terminal().Height,
+	}
+	paged := *orig
+	paged.Out = p
+	if err := rt.SetStream(&paged); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		err := p.Close()
+		if serr := rt.SetStream(orig); err == nil {
+			err = serr
+		}
+		return err
+	}, nil
+}
+
+// pagerWriter buffers output until it overflows the terminal, then starts
+// the pager and streams into its stdin.
+type pagerWriter struct {
+	ctx    context.Context
+	rt     *Runtime
+	argv   []string
+	out    io.Writer
+	height int
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	lines   int
+	pw      *io.PipeWriter
+	read    *countingReader
+	done    chan struct{}
+	runErr  error
+	started bool
+	closed  bool
+}
+
+// errPagerExited closes the pipe once the pager process is gone.
+var errPagerExited = errors.New("pager exited")
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if p.started {
+		return p.writePager(b)
+	}
+
+	p.buf.Write(b)
+	p.lines += bytes.Count(b, []byte{'\n'})
+	if p.height > 0 && p.lines <= p.height {
+		return len(b), nil
+	}
+	p.start()
+	pending := p.buf.Bytes()
+	p.buf = bytes.Buffer{}
+	if _, err := p.writePager(pending); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close flushes buffered output, closes the pager's stdin and waits for it
+// to exit.
+func (p *pagerWriter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	if !p.started {
+		_, err := p.out.Write(p.buf.Bytes())
+		return err
+	}
+	_ = p.pw.Close()
+	<-p.done
+	if p.runErr != nil {
+		p.rt.Logger().Debug("pager exited with error",
+			"pager", p.argv, "error", p.runErr)
+	}
+	return nil
+}
+
+// start launches the pager. Caller must hold p.mu.
+func (p *pagerWriter) start() {
+	pr, pw := io.Pipe()
+	p.pw = pw
+	p.read = &countingReader{r: pr}
+	p.done = make(chan struct{})
+	p.started = true
+
+	cmd := p.rt.Command(p.ctx, p.argv[0], p.argv[1:]...)
+	cmd.Stdin = p.read
+	cmd.Stdout = p.out
+	go func() {
+		defer close(p.done)
+		p.runErr = cmd.Run()
+		_ = pr.CloseWithError(errPagerExited)
+	}()
+}
+
+// writePager writes b to the pager. Once the pager has exited, remaining
+// output is discarded, or sent to the original stdout if the pager failed to
+// start. Caller must hold p.mu.
+func (p *pagerWriter) writePager(b []byte) (int, error) {
+	n, err := p.pw.Write(b)
+	if err == nil {
+		return n, nil
+	}
+	<-p.done
+	var exitErr *ExitError
+	if p.runErr != nil && !errors.As(p.runErr, &exitErr) && p.read.count() == 0 {
+		if _, err := p.out.Write(b[n:]); err != nil {
+			return n, err
+		}
+	}
+	return len(b), nil
+}
+
+// countingReader records how many bytes the pager consumed.
+type countingReader struct {
+	r  io.Reader
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.mu.Lock()
+	c.n += int64(n)
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *countingReader) count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
@@ -0,0 +1,201 @@
+package toolkit_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagerSandbox returns a sandbox with a 5 line terminal whose stdout is
+// captured in the returned buffer.
+func newPagerSandbox(t *testing.T, tty bool, opts ...tu.Option) (*tu.Sandbox, *bytes.Buffer) {
+	t.Helper()
+	opts = append([]tu.Option{tu.WithTTY(80, 5)}, opts...)
+	sb := tu.NewSandbox(t, nil, opts...)
+	out := &bytes.Buffer{}
+	require.NoError(t, sb.Runtime().SetStream(&toolkit.Stream{
+		In:    strings.NewReader(""),
+		Out:   out,
+		Err:   io.Discard,
+		IsTTY: tty,
+	}))
+	return sb, out
+}
+
+// catPager scripts a pager that copies stdin to stdout with a prefix.
+func catPager(ctx context.Context, cmd *toolkit.Command) (int, error) {
+	data, err := io.ReadAll(cmd.Stdin)
+	if err != nil {
+		return 1, err
+	}
+	_, err = fmt.Fprintf(cmd.Stdout, "[paged]%s", data)
+	return 0, err
+}
+
+func writeLines(t *testing.T, rt *toolkit.Runtime, n int) string {
+	t.Helper()
+	var want strings.Builder
+	for i := range n {
+		line := fmt.Sprintf("line %d\n", i)
+		want.WriteString(line)
+		_, err := io.WriteString(rt.Stream().Out, line)
+		require.NoError(t, err)
+	}
+	return want.String()
+}
+
+func TestPager_LongOutputIsPaged(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true)
+	sb.Commands().On("less", "-FRX").Do(catPager)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	want := writeLines(t, rt, 20)
+	require.NoError(t, done())
+
+	assert.Equal(t, "[paged]"+want, out.String())
+	assert.Same(t, out, rt.Stream().Out, "stream is restored")
+	calls := sb.Commands().Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"less", "-FRX"}, calls[0].Argv)
+}
+
+func TestPager_ShortOutputIsWrittenDirectly(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	want := writeLines(t, rt, 3)
+	require.NoError(t, done())
+
+	assert.Equal(t, want, out.String())
+	assert.Empty(t, sb.Commands().Calls())
+}
+
+func TestPager_OutputFillingTerminalIsWrittenDirectly(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	want := writeLines(t, rt, 5)
+	require.NoError(t, done())
+
+	assert.Equal(t, want, out.String())
+	assert.Empty(t, sb.Commands().Calls())
+}
+
+func TestPager_NoopWhenNotTTY(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		tty  bool
+		opts []tu.Option
+	}{
+		{name: "Piped"},
+		{name: "PagerCat", tty: true, opts: []tu.Option{tu.WithEnv("PAGER", "cat")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sb, out := newPagerSandbox(t, tc.tty, tc.opts...)
+			rt := sb.Runtime()
+
+			done, err := toolkit.Pager(sb.Context(), rt)
+			require.NoError(t, err)
+			assert.Same(t, out, rt.Stream().Out)
+			want := writeLines(t, rt, 20)
+			require.NoError(t, done())
+
+			assert.Equal(t, want, out.String())
+			assert.Empty(t, sb.Commands().Calls())
+		})
+	}
+}
+
+func TestPager_UsesPagerEnv(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true, tu.WithEnv("PAGER", `"/opt/my pager/bin/more" -s`))
+	sb.Commands().On("/opt/my pager/bin/more", "-s").Do(catPager)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	want := writeLines(t, rt, 10)
+	require.NoError(t, done())
+	assert.Equal(t, "[paged]"+want, out.String())
+}
+
+func TestPager_EarlyExitDiscardsRemainingOutput(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true)
+	sb.Commands().On("less", "...").Do(func(ctx context.Context, cmd *toolkit.Command) (int, error) {
+		buf := make([]byte, 7)
+		if _, err := io.ReadFull(cmd.Stdin, buf); err != nil {
+			return 1, err
+		}
+		_, err := cmd.Stdout.Write(buf)
+		return 0, err
+	})
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	writeLines(t, rt, 1000)
+	require.NoError(t, done())
+	assert.Equal(t, "line 0\n", out.String())
+}
+
+func TestPager_FallsBackWhenPagerMissing(t *testing.T) {
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	want := writeLines(t, rt, 20)
+	require.NoError(t, done())
+	assert.Equal(t, want, out.String())
+}
+
+func TestPager_EarlyExitWithOsCommander(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires head")
+	}
+	t.Parallel()
+
+	sb, out := newPagerSandbox(t, true,
+		tu.WithCommander(&toolkit.OsCommander{}),
+		tu.WithEnv("PATH", os.Getenv("PATH")),
+		tu.WithEnv("PAGER", "head -n 2"),
+		tu.WithWd("/"),
+	)
+	rt := sb.Runtime()
+
+	done, err := toolkit.Pager(sb.Context(), rt)
+	require.NoError(t, err)
+	writeLines(t, rt, 100000)
+	require.NoError(t, done())
+	assert.Equal(t, "line 0\nline 1\n", out.String())
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/jlrickert/cli-toolkit/clock"
@@ -12,6 +13,7 @@ import (
 // ProcessInfo identifies the current process for lock ownership and stale lock
 // detection.
 type ProcessInfo struct {
+	Name      string    // command name, the base of os.Args[0]
 	PID       int       // os.Getpid()
 	Hostname  string    // os.Hostname()
 	StartedAt time.Time // process start time
@@ -21,7 +23,12 @@ type ProcessInfo struct {
 // NewProcessInfo creates ProcessInfo for the current OS process.
 func NewProcessInfo(c clock.Clock) ProcessInfo {
 	hostname, _ := os.Hostname()
+	var name string
+	if len(os.Args) > 0 {
+		name = filepath.Base(os.Args[0])
+	}
 	return ProcessInfo{
+		Name:      name,
 		PID:       os.Getpid(),
 		Hostname:  hostname,
 		StartedAt: c.Now(),
@@ -0,0 +1,190 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+// Bar is a progress bar for work with a known or unknown total. A Bar is safe
+// for concurrent use.
+type Bar struct {
+	mu       sync.Mutex
+	label    string
+	current  int64
+	total    int64
+	start    time.Time
+	finished bool
+	width    int
+
+	// r is nil when the bar is drawn by a Multi.
+	r *renderer
+}
+
+// NewBar starts a progress bar for total units of work labelled label. A
+// total of zero or less shows a running count instead of a bar.
+func NewBar(rt *toolkit.Runtime, total int64, label string, opts ...Option) *Bar {
+	o := applyOptions(opts)
+	b := &Bar{label: label, total: total, width: o.barWidth}
+	b.r = newRenderer(rt, o, func(f frame) []string {
+		return []string{b.line(f)}
+	})
+	b.start = b.r.clk.Now()
+	b.r.start()
+	return b
+}
+
+// Add advances the bar by n units.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	b.current += n
+	b.mu.Unlock()
+}
+
+// Set sets the number of completed units.
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	b.current = n
+	b.mu.Unlock()
+}
+
+// SetTotal changes the total number of units.
+func (b *Bar) SetTotal(n int64) {
+	b.mu.Lock()
+	b.total = n
+	b.mu.Unlock()
+}
+
+// SetLabel changes the label shown before the bar.
+func (b *Bar) SetLabel(label string) {
+	b.mu.Lock()
+	b.label = label
+	b.mu.Unlock()
+}
+
+// Current returns the number of completed units.
+func (b *Bar) Current() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current
+}
+
+// Finish marks the bar complete and writes its final state. For a bar created
+// by [Multi.Bar] the final state is written when the Multi finishes.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	b.finished = true
+	b.mu.Unlock()
+	if b.r != nil {
+		b.r.stop()
+	}
+}
+
+func (b *Bar) line(f frame) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := f.now.Sub(b.start)
+	done := f.final || b.finished
+
+	if !f.tty {
+		line := b.label + ": " + b.stats(" (%d/%d)")
+		if done {
+			line += " done in " + formatDuration(elapsed)
+		}
+		return line
+	}
+
+	stats := b.stats(" %d/%d")
+	switch {
+	case done:
+		stats += " " + formatDuration(elapsed)
+	case b.total > 0 && b.current > 0 && b.current < b.total:
+		eta := time.Duration(float64(elapsed) * float64(b.total-b.current) / float64(b.current))
+		stats += " ETA " + formatDuration(eta)
+	}
+	if b.total <= 0 {
+		return fit(b.label+" "+stats, f.width)
+	}
+
+	cells := b.width
+	if f.width > 0 {
+		// Shrink to fit, dropping the bar once it becomes too short to read.
+		avail := f.width - 1 - len([]rune(b.label)) - len(stats) - 4
+		if avail < cells {
+			if avail < 5 {
+				return fit(b.label+" "+stats, f.width)
+			}
+			cells = avail
+		}
+	}
+	fill, empty := "#", "."
+	if f.unicode {
+		fill, empty = "█", "░"
+	}
+	n := int(int64(cells) * min(b.current, b.total) / b.total)
+	bar := strings.Repeat(fill, n) + strings.Repeat(empty, cells-n)
+	return fit(b.label+" ["+bar+"] "+stats, f.width)
+}
+
+// stats formats the percentage followed by counts using countFmt. The caller
+// must hold b.mu.
+func (b *Bar) stats(countFmt string) string {
+	if b.total <= 0 {
+		return fmt.Sprintf("%d", b.current)
+	}
+	pct := min(b.current*100/b.total, 100)
+	return fmt.Sprintf("%3d%%"+countFmt, pct, b.current, b.total)
+}
+
+// Multi draws several bars as a block of lines. Bars are added with
+// [Multi.Bar]; Multi is safe for concurrent use.
+type Multi struct {
+	mu    sync.Mutex
+	bars  []*Bar
+	width int
+	r     *renderer
+}
+
+// NewMulti starts an empty group of progress bars.
+func NewMulti(rt *toolkit.Runtime, opts ...Option) *Multi {
+	o := applyOptions(opts)
+	m := &Multi{width: o.barWidth}
+	m.r = newRenderer(rt, o, m.lines)
+	m.r.start()
+	return m
+}
+
+// Bar adds a bar to the group and returns it.
+func (m *Multi) Bar(total int64, label string) *Bar {
+	b := &Bar{
+		label: label,
+		total: total,
+		width: m.width,
+		start: m.r.clk.Now(),
+	}
+	m.mu.Lock()
+	m.bars = append(m.bars, b)
+	m.mu.Unlock()
+	return b
+}
+
+// Finish stops refreshing and writes the final state of every bar.
+func (m *Multi) Finish() {
+	m.r.stop()
+}
+
+func (m *Multi) lines(f frame) []string {
+	m.mu.Lock()
+	bars := append([]*Bar(nil), m.bars...)
+	m.mu.Unlock()
+
+	lines := make([]string, 0, len(bars))
+	for _, b := range bars {
+		lines = append(lines, b.line(f))
+	}
+	return lines
+}
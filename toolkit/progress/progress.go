@@ -0,0 +1,237 @@
+// Package progress renders progress bars and spinners over a
+// [toolkit.Runtime] stream.
+//
+// Widgets write to Stream.Err so Stream.Out stays clean for piping. Frames
+// are refreshed by a ticker from the runtime SchedulingClock, which means
+// TestClock.Advance drives rendering in tests. When Stream.IsTTY is set,
+// widgets redraw in place using ANSI cursor movement; otherwise they write a
+// plain status line whenever it changes, at most once per log interval, so CI
+// logs stay readable.
+//
+// Every widget must be finished with its Finish method, which stops the
+// ticker and writes the final state.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+const (
+	// DefaultInterval is the redraw interval on a terminal.
+	DefaultInterval = 100 * time.Millisecond
+	// DefaultLogInterval is the interval between plain status lines when
+	// output is not a terminal.
+	DefaultLogInterval = 5 * time.Second
+	// DefaultBarWidth is the preferred number of cells in a bar.
+	DefaultBarWidth = 30
+)
+
+// Option configures a widget.
+type Option func(*options)
+
+type options struct {
+	interval    time.Duration
+	logInterval time.Duration
+	barWidth    int
+}
+
+// WithInterval sets the redraw interval used on a terminal.
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithLogInterval sets the interval between plain status lines used when
+// output is not a terminal.
+func WithLogInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.logInterval = d
+		}
+	}
+}
+
+// WithBarWidth sets the preferred number of cells in a bar. Bars shrink to
+// fit narrower terminals.
+func WithBarWidth(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.barWidth = n
+		}
+	}
+}
+
+func applyOptions(opts []Option) *options {
+	o := &options{
+		interval:    DefaultInterval,
+		logInterval: DefaultLogInterval,
+		barWidth:    DefaultBarWidth,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// frame describes the frame being drawn.
+type frame struct {
+	n       int
+	tty     bool
+	unicode bool
+	width   int
+	final   bool
+	now     time.Time
+}
+
+// renderer owns the ticker goroutine and output for one or more widgets.
+type renderer struct {
+	w     io.Writer
+	clk   clock.SchedulingClock
+	tty   bool
+	term  toolkit.Terminal
+	opts  *options
+	lines func(f frame) []string
+
+	mu     sync.Mutex
+	n      int
+	drawn  int
+	logged []string
+
+	ticker   clock.Ticker
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newRenderer(rt *toolkit.Runtime, o *options, lines func(f frame) []string) *renderer {
+	s := toolkit.OrDefaultStream(rt.Stream())
+	w := s.Err
+	if w == nil {
+		w = io.Discard
+	}
+	clk := rt.SchedulingClock()
+	if clk == nil {
+		clk = clock.OsClock{}
+	}
+	return &renderer{
+		w:       w,
+		clk:     clk,
+		tty:     s.IsTTY,
+		term:    rt.Terminal(),
+		opts:    o,
+		lines:   lines,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// start draws the first frame and begins refreshing.
+func (r *renderer) start() {
+	interval := r.opts.logInterval
+	if r.tty {
+		interval = r.opts.interval
+	}
+	r.draw(false)
+	r.ticker = r.clk.NewTicker(interval)
+	go func() {
+		defer close(r.stopped)
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-r.ticker.C():
+				r.draw(false)
+			}
+		}
+	}()
+}
+
+// stop halts refreshing and draws the final frame. It is safe to call more
+// than once.
+func (r *renderer) stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+		r.ticker.Stop()
+		<-r.stopped
+		r.draw(true)
+	})
+}
+
+func (r *renderer) draw(final bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := frame{
+		n:       r.n,
+		tty:     r.tty,
+		unicode: r.term.Unicode,
+		width:   r.term.Width,
+		final:   final,
+		now:     r.clk.Now(),
+	}
+	r.n++
+	lines := r.lines(f)
+
+	var b strings.Builder
+	if r.tty {
+		if r.drawn > 1 {
+			fmt.Fprintf(&b, "\x1b[%dA", r.drawn-1)
+		}
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString("\r\x1b[2K")
+			b.WriteString(line)
+		}
+		r.drawn = len(lines)
+		if final {
+			b.WriteByte('\n')
+			r.drawn = 0
+		}
+	} else {
+		for i, line := range lines {
+			if i < len(r.logged) && r.logged[i] == line {
+				continue
+			}
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+		r.logged = lines
+	}
+	if b.Len() > 0 {
+		_, _ = io.WriteString(r.w, b.String())
+	}
+}
+
+// fit truncates line to the terminal width, leaving the last column free so
+// the cursor does not wrap.
+func fit(line string, width int) string {
+	if width <= 1 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) < width {
+		return line
+	}
+	return string(runes[:width-1])
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+	return d.Round(time.Second).String()
+}
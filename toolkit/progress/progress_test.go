@@ -0,0 +1,175 @@
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for the renderer goroutine and the test
+// to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newSandbox returns a sandbox whose stderr is captured in the returned
+// buffer and whose stdout must stay empty.
+func newSandbox(t *testing.T, tty bool, opts ...tu.Option) (*tu.Sandbox, *syncBuffer, *syncBuffer) {
+	t.Helper()
+	sb := tu.NewSandbox(t, nil, opts...)
+	out, errOut := &syncBuffer{}, &syncBuffer{}
+	require.NoError(t, sb.Runtime().SetStream(&toolkit.Stream{
+		In:    strings.NewReader(""),
+		Out:   out,
+		Err:   errOut,
+		IsTTY: tty,
+	}))
+	return sb, out, errOut
+}
+
+func waitFor(t *testing.T, buf *syncBuffer, want string) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), want)
+	}, time.Second, time.Millisecond, "waiting for %q in %q", want, buf.String())
+}
+
+func TestBar_TTYRedrawsOnTick(t *testing.T) {
+	t.Parallel()
+
+	sb, out, errOut := newSandbox(t, true, tu.WithTTY(80, 24))
+	bar := progress.NewBar(sb.Runtime(), 10, "copy", progress.WithBarWidth(10))
+	assert.Equal(t, "\r\x1b[2Kcopy [..........]   0% 0/10", errOut.String())
+
+	bar.Add(5)
+	sb.Advance(progress.DefaultInterval)
+	waitFor(t, errOut, "\r\x1b[2Kcopy [#####.....]  50% 5/10 ETA 0s")
+
+	bar.Set(10)
+	sb.Advance(900 * time.Millisecond)
+	bar.Finish()
+	assert.True(t, strings.HasSuffix(errOut.String(), "\r\x1b[2Kcopy [##########] 100% 10/10 1s\n"))
+	assert.Empty(t, out.String())
+}
+
+func TestBar_PlainLogLines(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, false)
+	bar := progress.NewBar(sb.Runtime(), 10, "copy")
+
+	bar.Add(5)
+	sb.Advance(progress.DefaultInterval)
+	sb.Advance(progress.DefaultLogInterval - progress.DefaultInterval)
+	waitFor(t, errOut, "copy:  50% (5/10)\n")
+
+	bar.Set(10)
+	sb.Advance(time.Second)
+	bar.Finish()
+	assert.Equal(t,
+		"copy:   0% (0/10)\n"+
+			"copy:  50% (5/10)\n"+
+			"copy: 100% (10/10) done in 6s\n",
+		errOut.String())
+	assert.NotContains(t, errOut.String(), "\x1b")
+}
+
+func TestBar_UnknownTotalAndNarrowTerminal(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, true, tu.WithTTY(24, 10))
+	count := progress.NewBar(sb.Runtime(), 0, "files")
+	count.Add(42)
+	count.Finish()
+	assert.Contains(t, errOut.String(), "\r\x1b[2Kfiles 42 0s\n")
+
+	sb2, _, errOut2 := newSandbox(t, true, tu.WithTTY(24, 10))
+	bar := progress.NewBar(sb2.Runtime(), 100, "download")
+	bar.Finish()
+	for _, line := range strings.Split(errOut2.String(), "\r\x1b[2K") {
+		assert.Less(t, len(strings.TrimSuffix(line, "\n")), 24, "line %q", line)
+	}
+}
+
+func TestMulti_RedrawsBlock(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, true, tu.WithTTY(80, 24))
+	m := progress.NewMulti(sb.Runtime(), progress.WithBarWidth(4))
+	a := m.Bar(4, "a")
+	b := m.Bar(2, "b")
+
+	a.Add(2)
+	b.Add(2)
+	b.Finish()
+	sb.Advance(progress.DefaultInterval)
+	waitFor(t, errOut, "\r\x1b[2Ka [##..]  50% 2/4 ETA 0s\n\r\x1b[2Kb [####] 100% 2/2 0s")
+
+	sb.Advance(progress.DefaultInterval)
+	waitFor(t, errOut, "\x1b[1A")
+
+	a.Add(2)
+	m.Finish()
+	assert.True(t, strings.HasSuffix(errOut.String(),
+		"\x1b[1A\r\x1b[2Ka [####] 100% 4/4 0s\n\r\x1b[2Kb [####] 100% 2/2 0s\n"))
+}
+
+func TestSpinner_TTYFrames(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, true, tu.WithTTY(80, 24))
+	s := progress.NewSpinner(sb.Runtime(), "build")
+	assert.Equal(t, "\r\x1b[2K| build", errOut.String())
+
+	sb.Advance(progress.DefaultInterval)
+	waitFor(t, errOut, "\r\x1b[2K/ build")
+
+	s.SetLabel("link")
+	sb.Advance(progress.DefaultInterval)
+	waitFor(t, errOut, "\r\x1b[2K- link")
+
+	s.Finish("built")
+	assert.True(t, strings.HasSuffix(errOut.String(), "\r\x1b[2Kbuilt in 0s\n"))
+}
+
+func TestSpinner_UnicodeFrames(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, true, tu.WithEnv("LANG", "en_US.UTF-8"))
+	s := progress.NewSpinner(sb.Runtime(), "sync")
+	s.Finish("")
+	assert.Equal(t, "\r\x1b[2K⠋ sync\r\x1b[2Ksync: done in 0s\n", errOut.String())
+}
+
+func TestSpinner_PlainLogLines(t *testing.T) {
+	t.Parallel()
+
+	sb, _, errOut := newSandbox(t, false)
+	s := progress.NewSpinner(sb.Runtime(), "build")
+	sb.Advance(progress.DefaultLogInterval)
+	waitFor(t, errOut, "build... (5s)\n")
+
+	s.Finish("")
+	assert.Equal(t, "build...\nbuild... (5s)\nbuild: done in 5s\n", errOut.String())
+}
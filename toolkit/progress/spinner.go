@@ -0,0 +1,75 @@
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+)
+
+var (
+	unicodeFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiFrames   = []string{"|", "/", "-", "\\"}
+)
+
+// Spinner shows activity for work of unknown length. A Spinner is safe for
+// concurrent use.
+type Spinner struct {
+	mu      sync.Mutex
+	label   string
+	message string
+	start   time.Time
+	r       *renderer
+}
+
+// NewSpinner starts a spinner labelled label.
+func NewSpinner(rt *toolkit.Runtime, label string, opts ...Option) *Spinner {
+	s := &Spinner{label: label}
+	s.r = newRenderer(rt, applyOptions(opts), func(f frame) []string {
+		return []string{s.line(f)}
+	})
+	s.start = s.r.clk.Now()
+	s.r.start()
+	return s
+}
+
+// SetLabel changes the text shown next to the spinner.
+func (s *Spinner) SetLabel(label string) {
+	s.mu.Lock()
+	s.label = label
+	s.mu.Unlock()
+}
+
+// Finish stops the spinner and writes message, or "<label>: done" when
+// message is empty, followed by the elapsed time.
+func (s *Spinner) Finish(message string) {
+	s.mu.Lock()
+	s.message = message
+	s.mu.Unlock()
+	s.r.stop()
+}
+
+func (s *Spinner) line(f frame) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := f.now.Sub(s.start)
+	if f.final {
+		msg := s.message
+		if msg == "" {
+			msg = s.label + ": done"
+		}
+		return fit(msg+" in "+formatDuration(elapsed), f.width)
+	}
+	if !f.tty {
+		if elapsed < time.Second {
+			return s.label + "..."
+		}
+		return s.label + "... (" + formatDuration(elapsed) + ")"
+	}
+	frames := asciiFrames
+	if f.unicode {
+		frames = unicodeFrames
+	}
+	return fit(frames[f.n%len(frames)]+" "+s.label, f.width)
+}
@@ -0,0 +1,221 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"golang.org/x/term"
+)
+
+// interactive reports whether s should get key-driven menus: output is a
+// terminal and input is not piped or redirected.
+func interactive(s *toolkit.Stream) bool {
+	return s.IsTTY && !s.IsPiped
+}
+
+// key is a key press understood by the interactive menus.
+type key int
+
+const (
+	keyUp key = iota + 1
+	keyDown
+	keyToggle
+	keyEnter
+)
+
+// readKey reads the next key press from s. Keys the menus do not use,
+// including unknown escape sequences, are skipped.
+func readKey(ctx context.Context, s *toolkit.Stream) (key, error) {
+	next := func() (byte, error) {
+		b, err := s.NextByte(ctx)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+			err = fmt.Errorf("%w: %w", ErrNoInput, err)
+		}
+		return b, err
+	}
+	for {
+		b, err := next()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case '\r', '\n':
+			return keyEnter, nil
+		case ' ':
+			return keyToggle, nil
+		case 'k':
+			return keyUp, nil
+		case 'j':
+			return keyDown, nil
+		case keyCtrlC:
+			return 0, ErrInterrupted
+		case keyCtrlD:
+			return 0, ErrNoInput
+		case keyEscape:
+			// Arrow keys arrive as ESC [ A or, in application mode, ESC O A.
+			if b, err = next(); err != nil {
+				return 0, err
+			}
+			if b != '[' && b != 'O' {
+				continue
+			}
+			if b, err = next(); err != nil {
+				return 0, err
+			}
+			switch b {
+			case 'A':
+				return keyUp, nil
+			case 'B':
+				return keyDown, nil
+			}
+		}
+	}
+}
+
+// rawMode puts s.In in raw mode when it is a terminal so keys arrive without
+// waiting for Enter. The returned function restores the previous state.
+func rawMode(s *toolkit.Stream) (restore func(), err error) {
+	f, ok := s.In.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return func() {}, nil
+	}
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = term.Restore(int(f.Fd()), state) }, nil
+}
+
+// menu draws a list of choices with a cursor, redrawing in place as the
+// cursor moves. Lines end in "\r\n" because raw mode turns off the
+// terminal's newline translation.
+type menu struct {
+	w       io.Writer
+	message string
+	choices []string
+	cursor  int
+	// checked marks selected choices in a multi-select menu; nil otherwise.
+	checked []bool
+	drawn   bool
+}
+
+func (m *menu) start(hint string) {
+	fmt.Fprintf(m.w, "%s (%s)\r\n", m.message, hint)
+	m.draw()
+}
+
+func (m *menu) draw() {
+	var b strings.Builder
+	if m.drawn {
+		fmt.Fprintf(&b, "\x1b[%dA", len(m.choices))
+	}
+	for i, c := range m.choices {
+		b.WriteString("\r\x1b[2K")
+		if i == m.cursor {
+			b.WriteString("> ")
+		} else {
+			b.WriteString("  ")
+		}
+		if m.checked != nil {
+			if m.checked[i] {
+				b.WriteString("[x] ")
+			} else {
+				b.WriteString("[ ] ")
+			}
+		}
+		b.WriteString(c)
+		b.WriteString("\r\n")
+	}
+	m.drawn = true
+	io.WriteString(m.w, b.String())
+}
+
+// move moves the cursor by delta, wrapping at either end.
+func (m *menu) move(delta int) {
+	n := len(m.choices)
+	m.cursor = ((m.cursor+delta)%n + n) % n
+	m.draw()
+}
+
+// finish replaces the menu with a one-line summary of the answer.
+func (m *menu) finish(answer string) {
+	fmt.Fprintf(m.w, "\x1b[%dA\r\x1b[J%s: %s\r\n", len(m.choices)+1, m.message, answer)
+}
+
+func selectInteractive(ctx context.Context, s *toolkit.Stream, message string, choices []string, def int) (int, error) {
+	restore, err := rawMode(s)
+	if err != nil {
+		return -1, err
+	}
+	defer restore()
+
+	m := &menu{w: s.Err, message: message, choices: choices}
+	if def >= 0 && def < len(choices) {
+		m.cursor = def
+	}
+	m.start("arrows to move, enter to select")
+	for {
+		k, err := readKey(ctx, s)
+		if err != nil {
+			fmt.Fprint(s.Err, "\r\n")
+			return -1, err
+		}
+		switch k {
+		case keyUp:
+			m.move(-1)
+		case keyDown:
+			m.move(1)
+		case keyEnter:
+			m.finish(choices[m.cursor])
+			return m.cursor, nil
+		}
+	}
+}
+
+func multiSelectInteractive(ctx context.Context, s *toolkit.Stream, message string, choices []string, defaults []int) ([]int, error) {
+	restore, err := rawMode(s)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	m := &menu{w: s.Err, message: message, choices: choices, checked: make([]bool, len(choices))}
+	for _, d := range defaults {
+		if d >= 0 && d < len(choices) {
+			m.checked[d] = true
+		}
+	}
+	m.start("arrows to move, space to toggle, enter to confirm")
+	for {
+		k, err := readKey(ctx, s)
+		if err != nil {
+			fmt.Fprint(s.Err, "\r\n")
+			return nil, err
+		}
+		switch k {
+		case keyUp:
+			m.move(-1)
+		case keyDown:
+			m.move(1)
+		case keyToggle:
+			m.checked[m.cursor] = !m.checked[m.cursor]
+			m.draw()
+		case keyEnter:
+			picked := []int{}
+			var names []string
+			for i, ok := range m.checked {
+				if ok {
+					picked = append(picked, i)
+					names = append(names, choices[i])
+				}
+			}
+			m.finish(strings.Join(names, ", "))
+			return picked, nil
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Package prompt asks the user questions over a [toolkit.Runtime] stream.
+//
+// Prompts are written to Stream.Err so Stream.Out stays clean for piping.
+// When Stream.IsTTY is set and input is not piped, [Select] and [MultiSelect]
+// show a menu driven by the arrow keys (or j and k), space and Enter, and
+// [Password] reads without echo when Stream.In is a real terminal. Otherwise
+// every prompt degrades to reading one line at a time from Stream.In, which
+// keeps prompts usable in pipelines. Both forms are scriptable in tests by
+// writing answers, or key sequences such as "\x1b[B\n", to a sandbox.Process.
+//
+// Invalid answers print a short message and ask again. End of input returns
+// [ErrNoInput] rather than guessing.
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"golang.org/x/term"
+)
+
+// ErrNoInput is returned when input ends before an answer is read.
+var ErrNoInput = errors.New("prompt: no input")
+
+// ErrInterrupted is returned when the user presses Ctrl-C while a prompt has
+// the terminal in raw mode, where Ctrl-C does not raise a signal.
+var ErrInterrupted = errors.New("prompt: interrupted")
+
+// Option configures Input and Password prompts.
+type Option func(*options)
+
+type options struct {
+	def       string
+	hasDef    bool
+	validator func(string) error
+}
+
+// WithDefault sets the value returned when the user enters an empty line.
+func WithDefault(v string) Option {
+	return func(o *options) {
+		o.def = v
+		o.hasDef = true
+	}
+}
+
+// WithValidator sets a function that checks each answer. A non-nil error is
+// shown to the user and the question is asked again.
+func WithValidator(fn func(string) error) Option {
+	return func(o *options) {
+		o.validator = fn
+	}
+}
+
+// Confirm asks a yes/no question. An empty answer returns def.
+func Confirm(ctx context.Context, rt *toolkit.Runtime, message string, def bool) (bool, error) {
+	s := rt.Stream()
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		fmt.Fprintf(s.Err, "%s %s ", message, hint)
+		line, err := readLine(ctx, s)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(s.Err, "Please answer y or n.")
+	}
+}
+
+// Input asks for a single line of text.
+func Input(ctx context.Context, rt *toolkit.Runtime, message string, opts ...Option) (string, error) {
+	o := applyOptions(opts)
+	s := rt.Stream()
+	for {
+		if o.hasDef && o.def != "" {
+			fmt.Fprintf(s.Err, "%s [%s]: ", message, o.def)
+		} else {
+			fmt.Fprintf(s.Err, "%s: ", message)
+		}
+		line, err := readLine(ctx, s)
+		if err != nil {
+			return "", err
+		}
+		answer, ok := o.check(s.Err, strings.TrimSpace(line))
+		if ok {
+			return answer, nil
+		}
+	}
+}
+
+// Password asks for a secret. When Stream.IsTTY is set and Stream.In is a
+// terminal, input is read without echo; otherwise a plain line is read.
+func Password(ctx context.Context, rt *toolkit.Runtime, message string, opts ...Option) (string, error) {
+	o := applyOptions(opts)
+	s := rt.Stream()
+	for {
+		fmt.Fprintf(s.Err, "%s: ", message)
+		line, err := readSecret(ctx, s)
+		if err != nil {
+			return "", err
+		}
+		answer, ok := o.check(s.Err, line)
+		if ok {
+			return answer, nil
+		}
+	}
+}
+
+// Select asks the user to pick one of choices and returns its index. On a
+// terminal the user moves a cursor, which starts at def, and presses Enter.
+// Otherwise the answer may be the 1-based number or the exact choice text,
+// and def is the index returned for an empty answer; pass -1 to require an
+// answer.
+func Select(ctx context.Context, rt *toolkit.Runtime, message string, choices []string, def int) (int, error) {
+	if len(choices) == 0 {
+		return -1, fmt.Errorf("prompt: select %q: no choices", message)
+	}
+	s := rt.Stream()
+	if interactive(s) {
+		return selectInteractive(ctx, s, message, choices, def)
+	}
+	fmt.Fprintf(s.Err, "%s\n", message)
+	writeChoices(s.Err, choices)
+	for {
+		if def >= 0 && def < len(choices) {
+			fmt.Fprintf(s.Err, "Choice [%d]: ", def+1)
+		} else {
+			fmt.Fprint(s.Err, "Choice: ")
+		}
+		line, err := readLine(ctx, s)
+		if err != nil {
+			return -1, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" && def >= 0 && def < len(choices) {
+			return def, nil
+		}
+		if i, ok := parseChoice(line, choices); ok {
+			return i, nil
+		}
+		fmt.Fprintf(s.Err, "Enter a number between 1 and %d.\n", len(choices))
+	}
+}
+
+// MultiSelect asks the user to pick any number of choices and returns their
+// indexes. On a terminal the user toggles choices, starting from defaults,
+// with space and confirms with Enter; indexes are returned in choice order.
+// Otherwise answers are separated by commas or spaces and may be numbers or
+// choice text, returned in the order given; "none" selects nothing and an
+// empty answer returns defaults.
+func MultiSelect(ctx context.Context, rt *toolkit.Runtime, message string, choices []string, defaults []int) ([]int, error) {
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("prompt: multi-select %q: no choices", message)
+	}
+	s := rt.Stream()
+	if interactive(s) {
+		return multiSelectInteractive(ctx, s, message, choices, defaults)
+	}
+	fmt.Fprintf(s.Err, "%s\n", message)
+	writeChoices(s.Err, choices)
+	for {
+		if len(defaults) > 0 {
+			nums := make([]string, 0, len(defaults))
+			for _, d := range defaults {
+				nums = append(nums, strconv.Itoa(d+1))
+			}
+			fmt.Fprintf(s.Err, "Choices [%s]: ", strings.Join(nums, ","))
+		} else {
+			fmt.Fprint(s.Err, "Choices: ")
+		}
+		line, err := readLine(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		switch strings.ToLower(line) {
+		case "":
+			return append([]int(nil), defaults...), nil
+		case "none":
+			return []int{}, nil
+		}
+
+		var picked []int
+		seen := make(map[int]bool)
+		valid := true
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+			i, ok := parseChoice(field, choices)
+			if !ok {
+				valid = false
+				break
+			}
+			if !seen[i] {
+				seen[i] = true
+				picked = append(picked, i)
+			}
+		}
+		if valid {
+			return picked, nil
+		}
+		fmt.Fprintf(s.Err, "Enter numbers between 1 and %d separated by commas.\n", len(choices))
+	}
+}
+
+func applyOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// check applies the default and validator to answer. It reports false after
+// printing the validation error so the caller can ask again.
+func (o *options) check(w io.Writer, answer string) (string, bool) {
+	if answer == "" && o.hasDef {
+		answer = o.def
+	}
+	if o.validator != nil {
+		if err := o.validator(answer); err != nil {
+			fmt.Fprintf(w, "Invalid value: %v\n", err)
+			return "", false
+		}
+	}
+	return answer, true
+}
+
+func writeChoices(w io.Writer, choices []string) {
+	for i, c := range choices {
+		fmt.Fprintf(w, "  %d) %s\n", i+1, c)
+	}
+}
+
+func parseChoice(s string, choices []string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= 1 && n <= len(choices) {
+			return n - 1, true
+		}
+		return -1, false
+	}
+	for i, c := range choices {
+		if strings.EqualFold(c, s) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// readLine reads the next line through [toolkit.Stream.ReadLine], so a
+// prompt stops waiting when ctx is cancelled and a line that arrives after it
+// gave up goes to the next prompt instead of being swallowed. End of input is
+// reported as [ErrNoInput].
+func readLine(ctx context.Context, s *toolkit.Stream) (string, error) {
+	line, err := s.ReadLine(ctx)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+		err = fmt.Errorf("%w: %w", ErrNoInput, err)
+	}
+	return line, err
+}
+
+// readSecret reads a line without echo when s.In is a terminal, by putting
+// the terminal in raw mode and reading keys through
+// [toolkit.Stream.NextByte]. Cancelling ctx restores the terminal and returns
+// ctx.Err without losing later input. Other readers are read with [readLine].
+func readSecret(ctx context.Context, s *toolkit.Stream) (string, error) {
+	f, ok := s.In.(*os.File)
+	if !ok || !s.IsTTY || !term.IsTerminal(int(f.Fd())) {
+		return readLine(ctx, s)
+	}
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = term.Restore(int(f.Fd()), state)
+		fmt.Fprintln(s.Err)
+	}()
+
+	var secret []byte
+	for {
+		b, err := s.NextByte(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("%w: %w", ErrNoInput, err)
+			}
+			return "", err
+		}
+		switch b {
+		case '\r', '\n':
+			return string(secret), nil
+		case keyCtrlC:
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(secret) == 0 {
+				return "", ErrNoInput
+			}
+		case keyBackspace, keyDelete:
+			if len(secret) > 0 {
+				secret = secret[:len(secret)-1]
+			}
+		default:
+			secret = append(secret, b)
+		}
+	}
+}
+
+// Control keys seen when a terminal is in raw mode.
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
@@ -0,0 +1,308 @@
+package prompt_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/jlrickert/cli-toolkit/toolkit/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runWithInput runs fn in a sandbox process fed with input and returns the
+// process result.
+func runWithInput(t *testing.T, input string, fn tu.Runner) *tu.ProcessResult {
+	t.Helper()
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(fn, false)
+	return proc.RunWithIO(sb.Context(), sb.Runtime(), strings.NewReader(input))
+}
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input string
+		def   bool
+		want  bool
+	}{
+		{name: "Yes", input: "y\n", want: true},
+		{name: "NoWord", input: "No\n", def: true, want: false},
+		{name: "EmptyUsesDefault", input: "\n", def: true, want: true},
+		{name: "RetriesInvalid", input: "maybe\nyes\n", want: true},
+		{name: "NoTrailingNewline", input: "y", want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var got bool
+			res := runWithInput(t, tc.input, func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+				var err error
+				got, err = prompt.Confirm(ctx, rt, "Continue?", tc.def)
+				return 0, err
+			})
+			require.NoError(t, res.Err)
+			assert.Equal(t, tc.want, got)
+			assert.Empty(t, res.Stdout, "prompts must not write to stdout")
+			assert.Contains(t, string(res.Stderr), "Continue?")
+		})
+	}
+}
+
+func TestConfirm_EOF(t *testing.T) {
+	t.Parallel()
+
+	res := runWithInput(t, "", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		_, err := prompt.Confirm(ctx, rt, "Continue?", true)
+		return 1, err
+	})
+	require.ErrorIs(t, res.Err, prompt.ErrNoInput)
+}
+
+func TestInput_DefaultAndValidator(t *testing.T) {
+	t.Parallel()
+
+	var name, port string
+	res := runWithInput(t, "\nabc\n8080\n", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		var err error
+		name, err = prompt.Input(ctx, rt, "Name", prompt.WithDefault("alice"))
+		if err != nil {
+			return 1, err
+		}
+		port, err = prompt.Input(ctx, rt, "Port", prompt.WithValidator(func(s string) error {
+			for _, r := range s {
+				if r < '0' || r > '9' {
+					return errors.New("must be a number")
+				}
+			}
+			return nil
+		}))
+		return 0, err
+	})
+	require.NoError(t, res.Err)
+	assert.Equal(t, "alice", name)
+	assert.Equal(t, "8080", port)
+	assert.Contains(t, string(res.Stderr), "Name [alice]: ")
+	assert.Contains(t, string(res.Stderr), "Invalid value: must be a number")
+}
+
+func TestPassword_NonTTYReadsLine(t *testing.T) {
+	t.Parallel()
+
+	var secret string
+	res := runWithInput(t, "s3cr3t\n", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		var err error
+		secret, err = prompt.Password(ctx, rt, "Password")
+		return 0, err
+	})
+	require.NoError(t, res.Err)
+	assert.Equal(t, "s3cr3t", secret)
+	assert.NotContains(t, string(res.Stderr), "s3cr3t")
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	choices := []string{"red", "green", "blue"}
+	var byNumber, byName, byDefault int
+	res := runWithInput(t, "2\n9\nBlue\n\n", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		var err error
+		if byNumber, err = prompt.Select(ctx, rt, "Color", choices, -1); err != nil {
+			return 1, err
+		}
+		if byName, err = prompt.Select(ctx, rt, "Color", choices, -1); err != nil {
+			return 1, err
+		}
+		byDefault, err = prompt.Select(ctx, rt, "Color", choices, 0)
+		return 0, err
+	})
+	require.NoError(t, res.Err)
+	assert.Equal(t, 1, byNumber)
+	assert.Equal(t, 2, byName)
+	assert.Equal(t, 0, byDefault)
+	assert.Contains(t, string(res.Stderr), "  3) blue\n")
+	assert.Contains(t, string(res.Stderr), "Enter a number between 1 and 3.")
+}
+
+func TestMultiSelect(t *testing.T) {
+	t.Parallel()
+
+	choices := []string{"lint", "test", "build"}
+	var picked, defaults, none []int
+	res := runWithInput(t, "3, lint 3\n\nnone\n", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		var err error
+		if picked, err = prompt.MultiSelect(ctx, rt, "Steps", choices, nil); err != nil {
+			return 1, err
+		}
+		if defaults, err = prompt.MultiSelect(ctx, rt, "Steps", choices, []int{0, 1}); err != nil {
+			return 1, err
+		}
+		none, err = prompt.MultiSelect(ctx, rt, "Steps", choices, []int{0})
+		return 0, err
+	})
+	require.NoError(t, res.Err)
+	assert.Equal(t, []int{2, 0}, picked)
+	assert.Equal(t, []int{0, 1}, defaults)
+	assert.Empty(t, none)
+	assert.Contains(t, string(res.Stderr), "Choices [1,2]: ")
+}
+
+// TestPrompt_ScriptedThroughProcessWrite drives prompts interactively by
+// writing answers to the process stdin while it runs.
+func TestPrompt_ScriptedThroughProcessWrite(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		name, err := prompt.Input(ctx, rt, "Name")
+		if err != nil {
+			return 1, err
+		}
+		ok, err := prompt.Confirm(ctx, rt, "Save "+name+"?", false)
+		if err != nil {
+			return 1, err
+		}
+		fmt.Fprintf(rt.Stream().Out, "%s %t\n", name, ok)
+		return 0, nil
+	}, true)
+
+	go func() {
+		_, _ = fmt.Fprintln(proc, "bob")
+		_, _ = fmt.Fprintln(proc, "y")
+	}()
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.NoError(t, res.Err)
+	assert.Equal(t, "bob true\n", string(res.Stdout))
+}
+
+func TestInput_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	ctx, cancel := context.WithCancel(sb.Context())
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		cancel()
+		_, err := prompt.Input(ctx, rt, "Name")
+		return 1, err
+	}, true)
+
+	res := proc.Run(ctx, sb.Runtime())
+	require.ErrorIs(t, res.Err, context.Canceled)
+}
+
+func TestInput_CancelledPromptKeepsLine(t *testing.T) {
+	t.Parallel()
+
+	var name string
+	res := runWithInput(t, "alice\n", func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := prompt.Input(cctx, rt, "Ignored"); !errors.Is(err, context.Canceled) {
+			return 1, fmt.Errorf("cancelled prompt: %w", err)
+		}
+		var err error
+		name, err = prompt.Input(ctx, rt, "Name")
+		return 0, err
+	})
+	require.NoError(t, res.Err)
+	assert.Equal(t, "alice", name)
+}
+
+// TestInput_InterruptedWhileWaiting cancels a prompt that is blocked reading
+// the process stdin by sending an interrupt.
+func TestInput_InterruptedWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		ctx, stop := rt.Signals().NotifyContext(ctx)
+		defer stop()
+		_, err := prompt.Input(ctx, rt, "Name")
+		return 1, err
+	}, true)
+	stderr := proc.StderrPipe()
+
+	go func() {
+		// Wait for the prompt so the signal arrives while the read blocks.
+		buf := make([]byte, len("Name: "))
+		_, _ = io.ReadFull(stderr, buf)
+		proc.Signal(os.Interrupt)
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, context.Canceled)
+}
+
+// typeIntoMenus writes each entry of keys to proc once the next menu hint is
+// shown on stderr. Writing before Run starts would mark stdin as piped and
+// select line-based input instead.
+func typeIntoMenus(proc *tu.Process, keys ...string) {
+	stderr := bufio.NewReader(proc.StderrPipe())
+	go func() {
+		for _, k := range keys {
+			for {
+				line, err := stderr.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if strings.Contains(line, "enter to") {
+					break
+				}
+			}
+			// Keep draining stderr while the menu consumes the keys.
+			go func() { _, _ = io.WriteString(proc, k) }()
+		}
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+}
+
+// TestSelect_InteractiveOnTTY drives the key-based menus with arrow keys, j/k
+// and space.
+func TestSelect_InteractiveOnTTY(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	choices := []string{"lint", "test", "build"}
+	var picked int
+	var toggled []int
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		var err error
+		if picked, err = prompt.Select(ctx, rt, "Step", choices, -1); err != nil {
+			return 1, err
+		}
+		toggled, err = prompt.MultiSelect(ctx, rt, "Steps", choices, []int{0})
+		return 0, err
+	}, true)
+	typeIntoMenus(proc, "\x1b[Bj\x1b[A\r", " j \x1bOB \n")
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.NoError(t, res.Err)
+	assert.Equal(t, 1, picked)
+	assert.Equal(t, []int{1, 2}, toggled)
+}
+
+func TestSelect_InteractiveCtrlC(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		_, err := prompt.Select(ctx, rt, "Step", []string{"a", "b"}, 0)
+		return 1, err
+	}, true)
+	typeIntoMenus(proc, "j\x03")
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, prompt.ErrInterrupted)
+}
@@ -1,6 +1,7 @@
 package toolkit
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -17,7 +18,13 @@ import (
 // Runtime is the explicit dependency container for commands and helpers.
 //
 // Context values are not used for mutable runtime dependencies; callers pass a
-// Runtime directly.
+// Runtime directly. Code that only receives a context can recover the runtime
+// with [RuntimeFrom] when the caller attached it with [WithRuntime].
+//
+// A Runtime may be read from several goroutines, but its setters, Setwd and
+// env mutations are not synchronized with each other. Give each goroutine
+// that changes its working directory or environment its own view with
+// [Runtime.Fork].
 type Runtime struct {
 	env     Env
 	fs      FileSystem
@@ -26,6 +33,17 @@ type Runtime struct {
 	stream  *Stream
 	hasher  Hasher
 	process *ProcessInfo
+	cmd     Commander
+	sigs    SignalSource
+
+	// loc is the time zone for [Runtime.Now]; see [WithRuntimeLocation].
+	loc *time.Location
+
+	// signals is the handler returned by [Runtime.Signals].
+	signals *Signals
+
+	// scope holds close hooks; see [Runtime.OnClose].
+	scope *closeScope
 
 	// jail and wd are canonical state managed by Runtime and applied to both
 	// env and filesystem.
@@ -36,16 +54,25 @@ type Runtime struct {
 // RuntimeOption mutates Runtime construction.
 type RuntimeOption func(*Runtime) error
 
-// NewRuntime constructs a Runtime with defaults and applies options.
-func NewRuntime(opts ...RuntimeOption) (*Runtime, error) {
-	rt := &Runtime{
+// defaultRuntime returns a Runtime holding the OS-backed defaults before
+// options and state normalization are applied.
+func defaultRuntime() *Runtime {
+	return &Runtime{
 		env:    &OsEnv{},
 		fs:     &OsFS{},
 		clock:  &clock.OsClock{},
 		logger: mylog.NewDiscardLogger(),
 		stream: DefaultStream(),
 		hasher: DefaultHasher,
+		cmd:    &OsCommander{},
+		sigs:   OsSignals{},
+		scope:  newCloseScope(nil),
 	}
+}
+
+// NewRuntime constructs a Runtime with defaults and applies options.
+func NewRuntime(opts ...RuntimeOption) (*Runtime, error) {
+	rt := defaultRuntime()
 
 	for _, opt := range opts {
 		if opt == nil {
@@ -59,10 +86,12 @@ func NewRuntime(opts ...RuntimeOption) (*Runtime, error) {
 	if err := rt.normalizeState(); err != nil {
 		return nil, err
 	}
+	rt.normalizeLocation()
 
 	if err := rt.Validate(); err != nil {
 		return nil, err
 	}
+	rt.signals = newSignals(rt)
 
 	return rt, nil
 }
@@ -133,6 +162,28 @@ func WithRuntimeHasher(h Hasher) RuntimeOption {
 	}
 }
 
+// WithRuntimeCommander sets the Commander used by [Runtime.Command].
+func WithRuntimeCommander(c Commander) RuntimeOption {
+	return func(rt *Runtime) error {
+		if c == nil {
+			return fmt.Errorf("runtime commander cannot be nil")
+		}
+		rt.cmd = c
+		return nil
+	}
+}
+
+// WithRuntimeSignalSource sets the SignalSource used by [Runtime.Signals].
+func WithRuntimeSignalSource(src SignalSource) RuntimeOption {
+	return func(rt *Runtime) error {
+		if src == nil {
+			return fmt.Errorf("runtime signal source cannot be nil")
+		}
+		rt.sigs = src
+		return nil
+	}
+}
+
 func WithProcessInfo(p ProcessInfo) RuntimeOption {
 	return func(rt *Runtime) error {
 		pi := p
@@ -201,7 +252,9 @@ func (rt *Runtime) applyWorkingDir(dir string) error {
 	if err := rt.env.Setwd(target); err != nil {
 		return err
 	}
-	if wd, err := rt.fs.Getwd(); err == nil && strings.TrimSpace(wd) != "" {
+	// Resolve target rather than reading back fs.Getwd: the filesystem may be
+	// shared with forks that change its working directory concurrently.
+	if wd, err := rt.fs.ResolvePath(target, false); err == nil && strings.TrimSpace(wd) != "" {
 		rt.wd = filepath.Clean(wd)
 		return nil
 	}
@@ -232,17 +285,27 @@ func (rt *Runtime) Validate() error {
 	if rt.hasher == nil {
 		return fmt.Errorf("runtime hasher is nil")
 	}
+	if rt.cmd == nil {
+		return fmt.Errorf("runtime commander is nil")
+	}
+	if rt.sigs == nil {
+		return fmt.Errorf("runtime signal source is nil")
+	}
 	return nil
 }
 
 // Clone returns a shallow clone of runtime dependencies and deep-copies Env and
-// Stream when supported.
+// Stream when supported. The clone has its own close scope nested in rt's; see
+// [Runtime.Close]. Closing rt reaches the clone only once it registers a hook
+// with [Runtime.OnClose], so a clone without hooks need not be closed, while
+// one with hooks stays tracked by rt until it is.
 func (rt *Runtime) Clone() *Runtime {
 	if rt == nil {
 		return nil
 	}
 
 	clone := *rt
+	clone.scope = newCloseScope(rt.closeScope())
 
 	if rt.env != nil {
 		if cloner, ok := rt.env.(EnvCloner); ok {
@@ -251,6 +314,9 @@ func (rt *Runtime) Clone() *Runtime {
 	}
 
 	if rt.stream != nil {
+		// Share buffered input so prompts on the clone and rt do not race
+		// for the same In.
+		rt.stream.prepareInput()
 		streamCopy := *rt.stream
 		clone.stream = &streamCopy
 	}
@@ -263,6 +329,55 @@ func (rt *Runtime) Clone() *Runtime {
 	return &clone
 }
 
+// Fork returns a clone of rt for use by another goroutine. Unlike
+// [Runtime.Clone], the fork always gets an environment of its own: env
+// implementations that cannot be copied, such as [OsEnv] which reads the
+// process environment and changes directory with os.Chdir, are replaced by an
+// in-memory snapshot. Changing the fork's environment or working directory
+// never affects rt or other forks.
+//
+// The filesystem, logger, clock, commander and signal handler are shared.
+// Runtime file operations resolve paths against the fork's own working
+// directory. Forking is safe while other goroutines read or fork rt. Close
+// rules are those of Clone: a fork that registers close hooks should be
+// closed when its work is done.
+func (rt *Runtime) Fork() *Runtime {
+	if rt == nil {
+		return nil
+	}
+	fork := rt.Clone()
+	if rt.env != nil {
+		fork.env = snapshotEnv(rt.env)
+	}
+	return fork
+}
+
+// snapshotEnv returns an independent copy of env.
+func snapshotEnv(env Env) Env {
+	if te, ok := env.(*TestEnv); ok {
+		return te.CloneEnv()
+	}
+	snap := &TestEnv{}
+	_ = snap.SetJail(env.GetJail())
+	for _, kv := range env.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			continue
+		}
+		_ = snap.Set(key, value)
+	}
+	if home, err := env.GetHome(); err == nil {
+		_ = snap.SetHome(home)
+	}
+	if user, err := env.GetUser(); err == nil {
+		_ = snap.SetUser(user)
+	}
+	if wd, err := env.Getwd(); err == nil {
+		_ = snap.Setwd(wd)
+	}
+	return snap
+}
+
 // Env returns the runtime Env dependency.
 func (rt *Runtime) Env() Env { return rt.env }
 
@@ -279,12 +394,16 @@ func (rt *Runtime) SchedulingClock() clock.SchedulingClock { return rt.clock }
 
 // SetClock updates the runtime clock dependency. If c implements
 // [clock.SchedulingClock] it is stored directly; otherwise it is wrapped with
-// OS-backed scheduling (see [WithRuntimeClock]).
+// OS-backed scheduling (see [WithRuntimeClock]). A [clock.TestClock] without
+// a location of its own is switched to the runtime time zone.
 func (rt *Runtime) SetClock(c clock.Clock) error {
 	if c == nil {
 		return fmt.Errorf("runtime clock cannot be nil")
 	}
 	rt.clock = clock.ToSchedulingClock(c)
+	if tc, ok := rt.clock.(*clock.TestClock); ok && tc.Location() == nil {
+		tc.SetLocation(rt.Location())
+	}
 	return nil
 }
 
@@ -326,6 +445,11 @@ func (rt *Runtime) Process() *ProcessInfo {
 	return rt.process
 }
 
+// SetProcessInfo updates the runtime process identity.
+func (rt *Runtime) SetProcessInfo(p ProcessInfo) {
+	rt.process = &p
+}
+
 // SetHasher updates the runtime hasher dependency.
 func (rt *Runtime) SetHasher(h Hasher) error {
 	if h == nil {
@@ -335,6 +459,94 @@ func (rt *Runtime) SetHasher(h Hasher) error {
 	return nil
 }
 
+// Commander returns the runtime Commander dependency.
+func (rt *Runtime) Commander() Commander { return rt.cmd }
+
+// SetCommander updates the runtime Commander dependency.
+func (rt *Runtime) SetCommander(c Commander) error {
+	if c == nil {
+		return fmt.Errorf("runtime commander cannot be nil")
+	}
+	rt.cmd = c
+	return nil
+}
+
+// SignalSource returns the runtime SignalSource dependency.
+func (rt *Runtime) SignalSource() SignalSource { return rt.sigs }
+
+// SetSignalSource updates the runtime SignalSource dependency. Handlers from
+// earlier [Runtime.Signals] calls keep the previous source.
+func (rt *Runtime) SetSignalSource(src SignalSource) error {
+	if src == nil {
+		return fmt.Errorf("runtime signal source cannot be nil")
+	}
+	rt.sigs = src
+	rt.signals = newSignals(rt)
+	return nil
+}
+
+// Command prepares an external command that runs through the runtime
+// [Commander]. The command inherits the runtime environment and stream, and
+// its Dir is the runtime working directory translated to a host path under
+// the jail. When [Runtime.LookPath] finds name, Path is set to its host path
+// so the program run is the one LookPath reports. Arguments are passed
+// through unchanged; use [Runtime.HostPath] for arguments that name runtime
+// paths.
+//
+// Errors resolving the working directory are reported when the command runs.
+func (rt *Runtime) Command(ctx context.Context, name string, args ...string) *Command {
+	c := &Command{
+		Name: name,
+		Args: args,
+		ctx:  ctx,
+	}
+	if err := rt.Validate(); err != nil {
+		c.err = err
+		return c
+	}
+	c.commander = rt.cmd
+	c.Stdin = rt.stream.In
+	c.Stdout = rt.stream.Out
+	c.Stderr = rt.stream.Err
+
+	dir, err := rt.HostPath(".")
+	if err != nil {
+		c.err = fmt.Errorf("command %q: resolve working directory: %w", name, err)
+		return c
+	}
+	c.Dir = dir
+	if p, err := rt.LookPath(name); err == nil {
+		c.Path = rt.hostPath(p)
+	}
+
+	env := rt.Environ()
+	if IsJailed(rt) {
+		env = append(env, "PWD="+dir)
+	}
+	c.Env = env
+	return c
+}
+
+// HostPath resolves rel like [Runtime.ResolvePath] and maps the result to the
+// host filesystem. When no jail is set the resolved path is returned as-is.
+// Use it for paths handed to external programs, which do not see the jail.
+func (rt *Runtime) HostPath(rel string) (string, error) {
+	path, err := rt.ResolvePath(rel, false)
+	if err != nil {
+		return "", err
+	}
+	return rt.hostPath(path), nil
+}
+
+// hostPath maps an already resolved runtime path to the host filesystem.
+func (rt *Runtime) hostPath(path string) string {
+	j := strings.TrimSpace(rt.GetJail())
+	if j == "" {
+		return path
+	}
+	return filepath.Join(j, strings.TrimPrefix(path, string(filepath.Separator)))
+}
+
 // --- Env forwarding methods ---
 
 func (rt *Runtime) Name() string {
@@ -8,7 +8,8 @@ import (
 )
 
 // NewTestRuntime constructs a runtime configured for tests with in-memory env
-// and a jailed filesystem. Callers may override defaults via runtime options.
+// and a jailed filesystem. Times are reported in UTC regardless of the host
+// TZ. Callers may override defaults via runtime options.
 func NewTestRuntime(jail, home, user string, opts ...RuntimeOption) (*Runtime, error) {
 	env := NewTestEnv(jail, home, user)
 	wd, err := env.Getwd()
@@ -28,6 +29,7 @@ func NewTestRuntime(jail, home, user string, opts ...RuntimeOption) (*Runtime, e
 		WithRuntimeStream(DefaultStream()),
 		WithRuntimeHasher(&MD5Hasher{}),
 		WithRuntimeJail(jail),
+		WithRuntimeLocation(time.UTC),
 	}
 	baseOpts = append(baseOpts, opts...)
 	return NewRuntime(baseOpts...)
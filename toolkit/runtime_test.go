@@ -1,8 +1,10 @@
 package toolkit_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/jlrickert/cli-toolkit/toolkit"
@@ -248,3 +250,73 @@ func TestNewRuntime_NilOptionSkipped(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, rt)
 }
+
+func TestRuntime_ForkIsolatesEnvAndWd(t *testing.T) {
+	t.Parallel()
+
+	jail := t.TempDir()
+	rt, err := toolkit.NewTestRuntime(jail, "/home/testuser", "testuser")
+	require.NoError(t, err)
+	require.NoError(t, rt.Set("SHARED", "parent"))
+
+	const workers = 16
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fork := rt.Fork()
+			dir := fmt.Sprintf("/work/%d", i)
+			assert.NoError(t, fork.Mkdir(dir, 0o755, true))
+			assert.NoError(t, fork.Setwd(dir))
+			assert.NoError(t, fork.Set("SHARED", dir))
+			assert.NoError(t, fork.WriteFile("id.txt", []byte(dir), 0o644))
+
+			for range 20 {
+				wd, err := fork.Getwd()
+				assert.NoError(t, err)
+				assert.Equal(t, dir, wd)
+				assert.Equal(t, dir, fork.Get("SHARED"))
+				_ = rt.Get("SHARED")
+				_, _ = rt.Getwd()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "parent", rt.Get("SHARED"))
+	wd, err := rt.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/testuser", wd)
+	for i := range workers {
+		got, err := rt.ReadFile(fmt.Sprintf("/work/%d/id.txt", i))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("/work/%d", i), string(got))
+	}
+}
+
+func TestRuntime_ForkSnapshotsOsEnv(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewOsRuntime()
+	require.NoError(t, err)
+	before, err := os.Getwd()
+	require.NoError(t, err)
+
+	fork := rt.Fork()
+	assert.IsType(t, &toolkit.TestEnv{}, fork.Env())
+	assert.Equal(t, os.Getenv("PATH"), fork.Get("PATH"))
+
+	require.NoError(t, fork.Set("CLI_TOOLKIT_FORK_TEST", "1"))
+	assert.Empty(t, os.Getenv("CLI_TOOLKIT_FORK_TEST"))
+
+	dir := t.TempDir()
+	require.NoError(t, fork.Setwd(dir))
+	wd, err := fork.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, dir, wd)
+
+	after, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
@@ -0,0 +1,373 @@
+package toolkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShellSplit splits s into words using POSIX shell quoting rules and expands
+// parameters and a leading tilde using env. It is intended for command strings
+// read from configuration or environment variables such as $EDITOR.
+//
+// Supported syntax:
+//   - Words separated by unquoted blanks (space, tab, newline).
+//   - Single quotes preserve their contents literally.
+//   - Double quotes preserve blanks and allow $ expansion; a backslash escapes
+//     only $, `, ", \ and newline.
+//   - An unquoted backslash escapes the next character; backslash-newline is a
+//     line continuation.
+//   - $VAR, ${VAR}, ${VAR:-word}, ${VAR-word}, ${VAR:=word}, ${VAR=word},
+//     ${VAR:?msg}, ${VAR?msg}, ${VAR:+word} and ${VAR+word}.
+//   - A leading unquoted "~" or "~/" expands via [ExpandPath].
+//
+// As in a real shell, a word that is empty after unquoted expansion, such as
+// $UNSET, is dropped, while a quoted empty string is kept as an empty word.
+// Unlike a real shell, the result of an unquoted expansion is not split into
+// further words, and command substitution is rejected with [ErrShellSyntax].
+// If env is nil the real OS environment is used.
+func ShellSplit(env Env, s string) ([]string, error) {
+	if env == nil {
+		env = &OsEnv{}
+	}
+	p := &shellParser{env: env, src: s}
+	return p.split()
+}
+
+// ShellExpand expands parameters in s using the same rules as [ShellSplit]
+// but without word splitting or quote removal. A backslash before $ escapes
+// it and a leading "~" or "~/" expands via [ExpandPath]. All other characters
+// are copied unchanged.
+//
+// It is a stricter alternative to [ExpandEnv] for configuration values that
+// need defaults, for example "${XDG_DATA_HOME:-~/.local/share}/app".
+func ShellExpand(env Env, s string) (string, error) {
+	if env == nil {
+		env = &OsEnv{}
+	}
+	p := &shellParser{env: env, src: s}
+	return p.expand()
+}
+
+// shellParser is a single-pass lexer over src. Nested words such as the
+// default in ${VAR:-word} are handled by a fresh parser over the substring.
+type shellParser struct {
+	env Env
+	src string
+	pos int
+	// quoted reports whether the last word read contained quotes or a
+	// backslash, so an empty result is still a word.
+	quoted bool
+}
+
+func (p *shellParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *shellParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), ErrShellSyntax)
+}
+
+func (p *shellParser) split() ([]string, error) {
+	var words []string
+	for {
+		for !p.eof() && isShellBlank(p.src[p.pos]) {
+			p.pos++
+		}
+		if p.eof() {
+			return words, nil
+		}
+		w, err := p.word(true)
+		if err != nil {
+			return nil, err
+		}
+		if w == "" && !p.quoted {
+			continue
+		}
+		words = append(words, w)
+	}
+}
+
+// word reads a single word, performing quote removal and expansion. When
+// split is true the word ends at the first unquoted blank.
+func (p *shellParser) word(split bool) (string, error) {
+	var b strings.Builder
+	p.quoted = false
+	if err := p.tilde(&b, split); err != nil {
+		return "", err
+	}
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case split && isShellBlank(c):
+			return b.String(), nil
+		case c == '\'':
+			p.quoted = true
+			end := strings.IndexByte(p.src[p.pos+1:], '\'')
+			if end < 0 {
+				return "", p.errorf("unterminated single quote")
+			}
+			b.WriteString(p.src[p.pos+1 : p.pos+1+end])
+			p.pos += end + 2
+		case c == '"':
+			p.quoted = true
+			p.pos++
+			if err := p.doubleQuoted(&b); err != nil {
+				return "", err
+			}
+		case c == '\\':
+			p.quoted = true
+			p.pos++
+			if p.eof() {
+				b.WriteByte('\\')
+				continue
+			}
+			if p.src[p.pos] != '\n' {
+				b.WriteByte(p.src[p.pos])
+			}
+			p.pos++
+		case c == '$':
+			if err := p.param(&b); err != nil {
+				return "", err
+			}
+		case c == '`':
+			return "", p.errorf("command substitution is not supported")
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return b.String(), nil
+}
+
+// expand performs parameter and tilde expansion without quote removal.
+func (p *shellParser) expand() (string, error) {
+	var b strings.Builder
+	if err := p.tilde(&b, false); err != nil {
+		return "", err
+	}
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '$':
+			b.WriteByte('$')
+			p.pos += 2
+		case c == '$':
+			if err := p.param(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return b.String(), nil
+}
+
+func (p *shellParser) doubleQuoted(b *strings.Builder) error {
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return nil
+		case '\\':
+			if p.pos+1 < len(p.src) && strings.IndexByte("$`\"\\\n", p.src[p.pos+1]) >= 0 {
+				if next := p.src[p.pos+1]; next != '\n' {
+					b.WriteByte(next)
+				}
+				p.pos += 2
+				continue
+			}
+			b.WriteByte('\\')
+			p.pos++
+		case '$':
+			if err := p.param(b); err != nil {
+				return err
+			}
+		case '`':
+			return p.errorf("command substitution is not supported")
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return p.errorf("unterminated double quote")
+}
+
+// tilde expands a leading "~" or "~/" at the current position. Other forms
+// such as "~user" are left for the caller to copy literally.
+func (p *shellParser) tilde(b *strings.Builder, split bool) error {
+	if p.eof() || p.src[p.pos] != '~' {
+		return nil
+	}
+	next := p.pos + 1
+	if next < len(p.src) && p.src[next] != '/' && !(split && isShellBlank(p.src[next])) {
+		return nil
+	}
+	home, err := ExpandPath(p.env, "~")
+	if err != nil {
+		return err
+	}
+	b.WriteString(home)
+	p.pos = next
+	return nil
+}
+
+// param expands the parameter starting at the '$' under the cursor.
+func (p *shellParser) param(b *strings.Builder) error {
+	p.pos++
+	if p.eof() {
+		b.WriteByte('$')
+		return nil
+	}
+	c := p.src[p.pos]
+	switch {
+	case c == '{':
+		return p.braced(b)
+	case c == '(':
+		return p.errorf("command substitution is not supported")
+	case isShellNameStart(c):
+		start := p.pos
+		for !p.eof() && isShellNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		b.WriteString(p.env.Get(p.src[start:p.pos]))
+	default:
+		b.WriteByte('$')
+	}
+	return nil
+}
+
+func (p *shellParser) braced(b *strings.Builder) error {
+	start := p.pos + 1
+	end, err := p.matchBrace(start)
+	if err != nil {
+		return err
+	}
+	body := p.src[start:end]
+	p.pos = end + 1
+
+	n := 0
+	for n < len(body) && isShellNameChar(body[n]) {
+		n++
+	}
+	name, rest := body[:n], body[n:]
+	if name == "" || !isShellNameStart(name[0]) {
+		return p.errorf("bad substitution ${%s}", body)
+	}
+	value := p.env.Get(name)
+	if rest == "" {
+		b.WriteString(value)
+		return nil
+	}
+
+	colon := rest[0] == ':'
+	if colon {
+		rest = rest[1:]
+	}
+	if rest == "" || strings.IndexByte("-=?+", rest[0]) < 0 {
+		return p.errorf("bad substitution ${%s}", body)
+	}
+	op, word := rest[0], rest[1:]
+	unset := !p.env.Has(name) && value == ""
+	if colon && value == "" {
+		unset = true
+	}
+
+	switch op {
+	case '-':
+		if unset {
+			if value, err = p.subword(word); err != nil {
+				return err
+			}
+		}
+	case '=':
+		if unset {
+			if value, err = p.subword(word); err != nil {
+				return err
+			}
+			if err := p.env.Set(name, value); err != nil {
+				return fmt.Errorf("assign %s: %w", name, err)
+			}
+		}
+	case '?':
+		if unset {
+			msg, err := p.subword(word)
+			if err != nil {
+				return err
+			}
+			if msg == "" {
+				msg = "parameter null or not set"
+			}
+			return fmt.Errorf("%s: %s: %w", name, msg, ErrNoEnvKey)
+		}
+	case '+':
+		value = ""
+		if !unset {
+			if value, err = p.subword(word); err != nil {
+				return err
+			}
+		}
+	}
+	b.WriteString(value)
+	return nil
+}
+
+// matchBrace returns the index of the '}' closing the expansion whose body
+// starts at start, skipping quoted sections and nested ${...} expansions.
+func (p *shellParser) matchBrace(start int) (int, error) {
+	depth := 1
+	inDouble := false
+	for i := start; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case '"':
+			inDouble = !inDouble
+		case '\'':
+			if inDouble {
+				continue
+			}
+			end := strings.IndexByte(p.src[i+1:], '\'')
+			if end < 0 {
+				return 0, p.errorf("unterminated single quote")
+			}
+			i += end + 1
+		case '{':
+			if i > 0 && p.src[i-1] == '$' {
+				depth++
+			}
+		case '}':
+			if inDouble && depth == 1 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, p.errorf("unterminated ${")
+}
+
+// subword expands the word of a ${VAR:-word} style expansion. Quoting inside
+// the word carries over to the enclosing word so ${X:-""} still yields an
+// empty word.
+func (p *shellParser) subword(s string) (string, error) {
+	sub := &shellParser{env: p.env, src: s}
+	w, err := sub.word(false)
+	if sub.quoted {
+		p.quoted = true
+	}
+	return w, err
+}
+
+func isShellBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isShellNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isShellNameChar(c byte) bool {
+	return isShellNameStart(c) || (c >= '0' && c <= '9')
+}
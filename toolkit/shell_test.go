@@ -0,0 +1,162 @@
+package toolkit_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShellEnv(t *testing.T) *toolkit.TestEnv {
+	t.Helper()
+	env := toolkit.NewTestEnv("", filepath.FromSlash("/home/alice"), "alice")
+	require.NoError(t, env.Set("FOO", "bar"))
+	require.NoError(t, env.Set("SPACED", "a b"))
+	require.NoError(t, env.Set("EMPTY", ""))
+	return env
+}
+
+func TestShellSplit(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "Plain", in: "code --wait", want: []string{"code", "--wait"}},
+		{name: "ExtraBlanks", in: "  vim \t -n  ", want: []string{"vim", "-n"}},
+		{name: "Empty", in: "   ", want: nil},
+		{name: "DoubleQuotedPath", in: `"/Applications/My Editor" -w`, want: []string{"/Applications/My Editor", "-w"}},
+		{name: "SingleQuotesLiteral", in: `'$FOO \n' x`, want: []string{`$FOO \n`, "x"}},
+		{name: "EmptyQuotedWord", in: `a "" ''`, want: []string{"a", "", ""}},
+		{name: "BackslashEscape", in: `my\ editor\"`, want: []string{`my editor"`}},
+		{name: "LineContinuation", in: "a\\\nb", want: []string{"ab"}},
+		{name: "DoubleQuoteEscapes", in: `"\$FOO \a \""`, want: []string{`$FOO \a "`}},
+		{name: "Var", in: "$FOO/x ${FOO}y", want: []string{"bar/x", "bary"}},
+		{name: "ExpansionNotSplit", in: "$SPACED", want: []string{"a b"}},
+		{name: "QuotedExpansion", in: `"$SPACED c"`, want: []string{"a b c"}},
+		{name: "ColonDefaultOnEmpty", in: "${EMPTY:-dflt}", want: []string{"dflt"}},
+		{name: "DefaultKeepsEmpty", in: "x${EMPTY-dflt}", want: []string{"x"}},
+		{name: "EmptyExpansionDropped", in: "$MISSING vim $EMPTY", want: []string{"vim"}},
+		{name: "OnlyEmptyExpansion", in: "${EMPTY}", want: nil},
+		{name: "QuotedEmptyExpansionKept", in: `"$MISSING" vim`, want: []string{"", "vim"}},
+		{name: "QuotedEmptyDefaultKept", in: `a ${X:-""} b`, want: []string{"a", "", "b"}},
+		{name: "UnusedQuotedDefault", in: `${FOO:+''}x ${FOO:-""} ${MISSING:+""}`, want: []string{"x", "bar"}},
+		{name: "DefaultOnMissing", in: "${MISSING-dflt}", want: []string{"dflt"}},
+		{name: "NestedDefault", in: "${MISSING:-${FOO}/${OTHER:-z}}", want: []string{"bar/z"}},
+		{name: "QuotedDefault", in: `${MISSING:-"a }b"}`, want: []string{"a }b"}},
+		{name: "Alternate", in: "${FOO:+alt}|${MISSING:+alt}", want: []string{"alt|"}},
+		{name: "DollarLiteral", in: "$ $1 a$", want: []string{"$", "$1", "a$"}},
+		{name: "Tilde", in: "~ ~/bin ~bob a~", want: []string{
+			filepath.FromSlash("/home/alice"),
+			filepath.FromSlash("/home/alice/bin"),
+			"~bob",
+			"a~",
+		}},
+		{name: "QuotedTildeLiteral", in: `"~"`, want: []string{"~"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := toolkit.ShellSplit(newShellEnv(t), tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestShellSplit_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+	}{
+		{name: "UnterminatedSingle", in: "'abc"},
+		{name: "UnterminatedDouble", in: `"abc`},
+		{name: "UnterminatedBrace", in: "${FOO"},
+		{name: "BadSubstitution", in: "${FOO%bar}"},
+		{name: "EmptyName", in: "${}"},
+		{name: "CommandSubstitution", in: "$(whoami)"},
+		{name: "Backticks", in: "`whoami`"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := toolkit.ShellSplit(newShellEnv(t), tc.in)
+			require.ErrorIs(t, err, toolkit.ErrShellSyntax)
+		})
+	}
+}
+
+func TestShellSplit_RequiredParameter(t *testing.T) {
+	t.Parallel()
+	env := newShellEnv(t)
+
+	_, err := toolkit.ShellSplit(env, "${MISSING:?set MISSING first}")
+	require.ErrorIs(t, err, toolkit.ErrNoEnvKey)
+	assert.Contains(t, err.Error(), "MISSING: set MISSING first")
+
+	_, err = toolkit.ShellSplit(env, "${EMPTY:?}")
+	require.ErrorIs(t, err, toolkit.ErrNoEnvKey)
+
+	got, err := toolkit.ShellSplit(env, `"${EMPTY?}"`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, got)
+}
+
+func TestShellSplit_AssignDefault(t *testing.T) {
+	t.Parallel()
+	env := newShellEnv(t)
+
+	got, err := toolkit.ShellSplit(env, "${NEW:=value} $NEW")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value", "value"}, got)
+	assert.Equal(t, "value", env.Get("NEW"))
+}
+
+func TestShellExpand(t *testing.T) {
+	t.Parallel()
+	env := newShellEnv(t)
+
+	got, err := toolkit.ShellExpand(env, "${XDG_BIN_HOME:-~/.local/bin}/app")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/home/alice/.local/bin")+"/app", got)
+
+	got, err = toolkit.ShellExpand(env, `~/"$FOO" \$FOO 'x'`)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/home/alice")+`/"bar" $FOO 'x'`, got)
+
+	_, err = toolkit.ShellExpand(env, "${REQUIRED:?}")
+	require.ErrorIs(t, err, toolkit.ErrNoEnvKey)
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "/home/alice", "alice")
+	require.NoError(t, err)
+
+	argv, err := toolkit.EditorCommand(rt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{toolkit.DefaultEditor}, argv)
+
+	require.NoError(t, rt.Set("EDITOR", `"/opt/My Editor/edit" -w`))
+	argv, err = toolkit.EditorCommand(rt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/opt/My Editor/edit", "-w"}, argv)
+
+	require.NoError(t, rt.Set("VISUAL", "code --wait"))
+	argv, err = toolkit.EditorCommand(rt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"code", "--wait"}, argv)
+
+	require.NoError(t, rt.Set("VISUAL", `"unterminated`))
+	_, err = toolkit.EditorCommand(rt)
+	require.ErrorIs(t, err, toolkit.ErrShellSyntax)
+}
@@ -0,0 +1,198 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SignalSource delivers process signals. It mirrors [signal.Notify] and
+// [signal.Stop], and adds Exit for forced termination so tests can observe a
+// forced exit instead of losing the test binary.
+type SignalSource interface {
+	// Notify relays the given signals to c. Delivery must not block.
+	Notify(c chan<- os.Signal, sig ...os.Signal)
+	// Stop stops relaying signals to c.
+	Stop(c chan<- os.Signal)
+	// Exit terminates the process with code. It does not return.
+	Exit(code int)
+}
+
+// OsSignals is the production SignalSource backed by os/signal.
+type OsSignals struct{}
+
+// Notify implements [SignalSource] using signal.Notify.
+func (OsSignals) Notify(c chan<- os.Signal, sig ...os.Signal) { signal.Notify(c, sig...) }
+
+// Stop implements [SignalSource] using signal.Stop.
+func (OsSignals) Stop(c chan<- os.Signal) { signal.Stop(c) }
+
+// Exit implements [SignalSource] using os.Exit.
+func (OsSignals) Exit(code int) { os.Exit(code) }
+
+// ShutdownSignals are the signals [Signals] listens for.
+var ShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// SignalError is the context cause recorded when a signal cancels a context
+// from [Signals.NotifyContext].
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "received signal: " + e.Signal.String()
+}
+
+// ExitCode returns the conventional shell exit code for the signal, 128 plus
+// the signal number (130 for Ctrl-C).
+func (e *SignalError) ExitCode() int {
+	return signalExitCode(e.Signal)
+}
+
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// Signals coordinates interruption and graceful shutdown for a runtime.
+//
+// [Signals.NotifyContext] cancels a context on the first shutdown signal; a
+// second signal forces exit through the runtime [SignalSource]. Cleanup that
+// must run before exit is registered with [Signals.OnShutdown] and run by
+// [Signals.Shutdown]. Signals is safe for concurrent use.
+type Signals struct {
+	rt  *Runtime
+	src SignalSource
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+type shutdownHook struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+func newSignals(rt *Runtime) *Signals {
+	return &Signals{rt: rt, src: rt.sigs}
+}
+
+// Signals returns the runtime's signal handler. Clones and forks share the
+// handler until [Runtime.SetSignalSource] is called.
+func (rt *Runtime) Signals() *Signals {
+	if rt.signals == nil {
+		// Runtimes built without NewRuntime create the handler on first use.
+		rt.signals = newSignals(rt)
+	}
+	return rt.signals
+}
+
+// NotifyContext returns a copy of parent that is cancelled when one of
+// [ShutdownSignals] arrives. The cause, available from context.Cause, is a
+// [*SignalError]. A second signal before stop is called forces exit with
+// [SignalError.ExitCode].
+//
+// Call stop to release the signal subscription once the context is no longer
+// needed.
+func (s *Signals) NotifyContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	ch := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	s.src.Notify(ch, ShutdownSignals...)
+
+	go func() {
+		var first os.Signal
+		select {
+		case first = <-ch:
+		case <-done:
+			return
+		}
+		s.rt.Logger().Info("received signal, shutting down", "signal", first.String())
+		cancel(&SignalError{Signal: first})
+
+		select {
+		case sig := <-ch:
+			s.rt.Logger().Warn("received second signal, forcing exit", "signal", sig.String())
+			s.src.Exit(signalExitCode(sig))
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			s.src.Stop(ch)
+			close(done)
+			cancel(context.Canceled)
+		})
+	}
+}
+
+// OnShutdown registers fn to run during [Signals.Shutdown]. Hooks run one at
+// a time in registration order. Each receives a context cancelled after
+// timeout, measured by the runtime clock; a non-positive timeout means no
+// limit.
+func (s *Signals) OnShutdown(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, timeout: timeout, fn: fn})
+}
+
+// Shutdown runs the registered hooks and returns their errors joined. A hook
+// that outlives its timeout is abandoned with an error wrapping
+// context.DeadlineExceeded, and the next hook starts. If ctx ends first,
+// remaining hooks are skipped. Hooks run at most once.
+func (s *Signals) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := s.hooks
+	s.hooks = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i, h := range hooks {
+		if err := ctx.Err(); err != nil {
+			for _, skipped := range hooks[i:] {
+				errs = append(errs, fmt.Errorf("shutdown hook %q: %w", skipped.name, err))
+			}
+			break
+		}
+		if err := s.runHook(ctx, h); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook %q: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Signals) runHook(ctx context.Context, h shutdownHook) error {
+	hookCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Start the timer before the hook so a test clock sees it once the hook
+	// is running, and stop it on return so a hook that finishes early leaves
+	// no pending timer behind.
+	var timeout chan struct{}
+	if h.timeout > 0 {
+		timeout = make(chan struct{})
+		timer := s.rt.SchedulingClock().AfterFunc(h.timeout, func() { close(timeout) })
+		defer timer.Stop()
+	}
+	result := make(chan error, 1)
+	go func() { result <- h.fn(hookCtx) }()
+	select {
+	case err := <-result:
+		return err
+	case <-timeout:
+		cancel(context.DeadlineExceeded)
+		return fmt.Errorf("timed out after %s: %w", h.timeout, context.DeadlineExceeded)
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
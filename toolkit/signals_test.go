@@ -0,0 +1,140 @@
+package toolkit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jlrickert/cli-toolkit/clock"
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignals_NotifyContext(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	ctx, stop := sb.Runtime().Signals().NotifyContext(sb.Context())
+	defer stop()
+
+	sb.Signals().Send(syscall.SIGTERM)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled by SIGTERM")
+	}
+
+	var sigErr *toolkit.SignalError
+	require.ErrorAs(t, context.Cause(ctx), &sigErr)
+	assert.Equal(t, syscall.SIGTERM, sigErr.Signal)
+	assert.Equal(t, 143, sigErr.ExitCode())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestSignals_StopReleasesSubscription(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	ctx, stop := sb.Runtime().Signals().NotifyContext(sb.Context())
+	stop()
+
+	require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+	sb.Signals().Send(os.Interrupt)
+	_, exited := sb.Signals().ExitCode()
+	assert.False(t, exited)
+}
+
+func TestSignals_SecondInterruptForcesExit(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	proc := tu.NewProcess(func(ctx context.Context, rt *toolkit.Runtime) (int, error) {
+		ctx, stop := rt.Signals().NotifyContext(ctx)
+		defer stop()
+		<-ctx.Done()
+		// Simulate a shutdown that hangs until the user presses Ctrl-C again.
+		<-release
+		return 0, nil
+	}, true)
+	proc.Signal(os.Interrupt)
+	proc.Signal(os.Interrupt)
+
+	res := proc.Run(sb.Context(), sb.Runtime())
+	require.ErrorIs(t, res.Err, tu.ErrForcedExit)
+	assert.ErrorIs(t, res.Err, tu.ErrRunnerLeaked, "the hung shutdown ignores cancellation")
+	assert.Equal(t, 130, res.ExitCode)
+}
+
+func TestSignals_ShutdownHooks(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	sigs := sb.Runtime().Signals()
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	started := make(chan struct{})
+	sigs.OnShutdown("flush", time.Second, func(ctx context.Context) error {
+		record("flush")
+		return nil
+	})
+	sigs.OnShutdown("hang", 5*time.Second, func(ctx context.Context) error {
+		record("hang")
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	sigs.OnShutdown("close", 0, func(ctx context.Context) error {
+		record("close")
+		return errors.New("close failed")
+	})
+
+	errc := make(chan error, 1)
+	go func() { errc <- sigs.Shutdown(sb.Context()) }()
+
+	<-started
+	sb.Advance(5 * time.Second)
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-time.After(time.Second):
+		t.Fatal("shutdown did not finish after the hook timed out")
+	}
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, `shutdown hook "hang": timed out after 5s`)
+	assert.ErrorContains(t, err, `shutdown hook "close": close failed`)
+	assert.Equal(t, []string{"flush", "hang", "close"}, order)
+
+	// Hooks run at most once.
+	require.NoError(t, sigs.Shutdown(sb.Context()))
+}
+
+func TestSignals_FastHookLeavesNoTimer(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil)
+	tc, ok := sb.Runtime().SchedulingClock().(*clock.TestClock)
+	require.True(t, ok)
+
+	sigs := sb.Runtime().Signals()
+	sigs.OnShutdown("fast", time.Minute, func(ctx context.Context) error { return nil })
+
+	require.NoError(t, sigs.Shutdown(sb.Context()))
+	assert.Zero(t, tc.PendingCount())
+}
@@ -1,8 +1,13 @@
 package toolkit
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"io"
 	"os"
+	"strings"
+	"sync"
 )
 
 // Stream models the standard IO streams and common stream properties.
@@ -22,6 +27,20 @@ type Stream struct {
 	IsPiped bool
 	// IsTTY indicates whether stdout refers to a terminal.
 	IsTTY bool
+
+	// Width and Height, when positive, fix the terminal size reported by
+	// [DetectTerminal] instead of querying Out or COLUMNS and LINES. Tests
+	// use them to fake a terminal of a given size.
+	Width  int
+	Height int
+	// ForceColor makes [DetectTerminal] report Color regardless of IsTTY and
+	// the color variables in the env.
+	ForceColor bool
+	Color      ColorLevel
+
+	// input buffers reads of In for ReadLine and NextByte. Copies of the
+	// Stream share it so they never race each other for the same input.
+	input *streamInput
 }
 
 // DefaultStream returns a Stream configured with the real process
@@ -44,3 +63,141 @@ func OrDefaultStream(s *Stream) *Stream {
 	}
 	return DefaultStream()
 }
+
+// ReadLine reads the next line from In without the trailing newline or
+// carriage return. A final line without a newline is returned as-is; at end
+// of input the reader's error, such as io.EOF, is returned.
+//
+// The read can be abandoned by cancelling ctx, which returns ctx.Err. Input
+// that arrives afterwards, including a partly read line, is kept for the next
+// ReadLine or NextByte on the Stream or its copies, so prompts can share In
+// without swallowing each other's answers. In is read one byte at a time and
+// only while a read is requested, leaving input after the line unread.
+func (s *Stream) ReadLine(ctx context.Context) (string, error) {
+	in, err := s.acquireInput(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer in.release()
+
+	scanned := 0
+	for {
+		if i := bytes.IndexByte(in.buf[scanned:], '\n'); i >= 0 {
+			line := string(in.buf[:scanned+i])
+			in.buf = in.buf[scanned+i+1:]
+			return strings.TrimSuffix(line, "\r"), nil
+		}
+		scanned = len(in.buf)
+		b, err := in.next(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(in.buf) > 0 {
+				line := string(in.buf)
+				in.buf = nil
+				return strings.TrimSuffix(line, "\r"), nil
+			}
+			return "", err
+		}
+		in.buf = append(in.buf, b)
+	}
+}
+
+// NextByte reads a single byte from In with the same cancellation and
+// buffering rules as [Stream.ReadLine]. It suits key-at-a-time input from a
+// terminal in raw mode.
+func (s *Stream) NextByte(ctx context.Context) (byte, error) {
+	in, err := s.acquireInput(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer in.release()
+
+	if len(in.buf) > 0 {
+		b := in.buf[0]
+		in.buf = in.buf[1:]
+		return b, nil
+	}
+	return in.next(ctx)
+}
+
+// streamInputMu guards lazy creation of Stream.input.
+var streamInputMu sync.Mutex
+
+// streamInput reads a Stream's In in a background goroutine so a reader can
+// stop waiting when its context is cancelled. At most one read of In is in
+// flight; a byte that arrives after its reader gave up is kept for the next
+// one.
+type streamInput struct {
+	r    io.Reader
+	turn chan struct{} // holds one token; serializes readers
+	// buf holds bytes read from r but not yet consumed.
+	buf []byte
+	// pending is the result channel of the read in flight, or nil.
+	pending chan inputResult
+}
+
+type inputResult struct {
+	b   byte
+	err error
+}
+
+// prepareInput creates the shared input state so copies of s made afterwards
+// use it too.
+func (s *Stream) prepareInput() *streamInput {
+	streamInputMu.Lock()
+	defer streamInputMu.Unlock()
+	if s.input == nil || s.input.r != s.In {
+		in := &streamInput{r: s.In, turn: make(chan struct{}, 1)}
+		in.turn <- struct{}{}
+		s.input = in
+	}
+	return s.input
+}
+
+func (s *Stream) acquireInput(ctx context.Context) (*streamInput, error) {
+	if s == nil || s.In == nil {
+		return nil, io.EOF
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	in := s.prepareInput()
+	select {
+	case <-in.turn:
+		return in, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (in *streamInput) release() { in.turn <- struct{}{} }
+
+// next returns the next byte from r, waiting for the read in flight or
+// starting one. The caller holds the turn.
+func (in *streamInput) next(ctx context.Context) (byte, error) {
+	if in.pending == nil {
+		ch := make(chan inputResult, 1)
+		in.pending = ch
+		r := in.r
+		go func() {
+			var buf [1]byte
+			for {
+				n, err := r.Read(buf[:])
+				if n > 0 {
+					ch <- inputResult{b: buf[0]}
+					return
+				}
+				if err != nil {
+					ch <- inputResult{err: err}
+					return
+				}
+			}
+		}()
+	}
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case res := <-in.pending:
+		in.pending = nil
+		return res.b, res.err
+	}
+}
@@ -2,6 +2,8 @@ package toolkit_test
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"testing"
 
 	"github.com/jlrickert/cli-toolkit/toolkit"
@@ -41,3 +43,41 @@ func TestOrDefaultStream_NilReturnsDefault(t *testing.T) {
 	assert.NotNil(t, result.Out)
 	assert.NotNil(t, result.Err)
 }
+
+func TestStream_ReadLineKeepsAbandonedInput(t *testing.T) {
+	t.Parallel()
+
+	pr, pw := io.Pipe()
+	s := &toolkit.Stream{In: pr}
+
+	// A cancelled read leaves the line for the next read.
+	ctx, cancel := context.WithCancel(t.Context())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := s.ReadLine(ctx)
+		errc <- err
+	}()
+	_, err := pw.Write([]byte("ab"))
+	require.NoError(t, err)
+	cancel()
+	require.ErrorIs(t, <-errc, context.Canceled)
+
+	// Copies of the stream, as made by Runtime.Clone, share the input.
+	copied := *s
+	go func() {
+		_, _ = pw.Write([]byte("c\r\nrest"))
+		_ = pw.Close()
+	}()
+	line, err := copied.ReadLine(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", line)
+
+	b, err := s.NextByte(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, byte('r'), b)
+	line, err = s.ReadLine(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "est", line)
+	_, err = s.ReadLine(t.Context())
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,270 @@
+package toolkit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type colorKind uint8
+
+const (
+	colorUnset colorKind = iota
+	colorBasic
+	colorIndexed
+	colorRGB
+)
+
+// Color is a foreground or background color for a [Style]. The zero value
+// leaves the terminal default in place.
+//
+// Colors are downsampled to the terminal [ColorLevel] when rendered, so an
+// RGB color still produces a close match on a 16-color terminal.
+type Color struct {
+	kind    colorKind
+	n       uint8
+	r, g, b uint8
+}
+
+// The eight standard ANSI colors. Use [Color.Bright] for the bright variants.
+var (
+	Black   = BasicColor(0)
+	Red     = BasicColor(1)
+	Green   = BasicColor(2)
+	Yellow  = BasicColor(3)
+	Blue    = BasicColor(4)
+	Magenta = BasicColor(5)
+	Cyan    = BasicColor(6)
+	White   = BasicColor(7)
+)
+
+// BasicColor returns one of the 16 standard ANSI colors. Values above 15 wrap.
+func BasicColor(n uint8) Color {
+	return Color{kind: colorBasic, n: n % 16}
+}
+
+// IndexedColor returns a color from the xterm 256-color palette.
+func IndexedColor(n uint8) Color {
+	return Color{kind: colorIndexed, n: n}
+}
+
+// RGBColor returns a 24-bit color.
+func RGBColor(r, g, b uint8) Color {
+	return Color{kind: colorRGB, r: r, g: g, b: b}
+}
+
+// Bright returns the bright variant of a standard color. Other colors are
+// returned unchanged.
+func (c Color) Bright() Color {
+	if c.kind == colorBasic && c.n < 8 {
+		c.n += 8
+	}
+	return c
+}
+
+// IsZero reports whether c is the unset color.
+func (c Color) IsZero() bool {
+	return c.kind == colorUnset
+}
+
+// sgr returns the SGR parameters selecting c at level. base is 30 for the
+// foreground and 40 for the background.
+func (c Color) sgr(level ColorLevel, base int) string {
+	if c.kind == colorUnset || level == ColorNone {
+		return ""
+	}
+	c = c.downsample(level)
+	switch c.kind {
+	case colorBasic:
+		if c.n < 8 {
+			return strconv.Itoa(base + int(c.n))
+		}
+		return strconv.Itoa(base + 60 + int(c.n) - 8)
+	case colorIndexed:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(int(c.n))
+	default:
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(c.r)) + ";" +
+			strconv.Itoa(int(c.g)) + ";" + strconv.Itoa(int(c.b))
+	}
+}
+
+func (c Color) downsample(level ColorLevel) Color {
+	switch {
+	case c.kind == colorRGB && level == Color256:
+		return IndexedColor(rgbToIndexed(c.r, c.g, c.b))
+	case c.kind == colorRGB && level == ColorBasic:
+		return BasicColor(rgbToBasic(c.r, c.g, c.b))
+	case c.kind == colorIndexed && level == ColorBasic:
+		if c.n < 16 {
+			return BasicColor(c.n)
+		}
+		r, g, b := indexedToRGB(c.n)
+		return BasicColor(rgbToBasic(r, g, b))
+	}
+	return c
+}
+
+// basicPalette approximates the xterm defaults for the 16 standard colors.
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+func indexedToRGB(n uint8) (uint8, uint8, uint8) {
+	switch {
+	case n < 16:
+		p := basicPalette[n]
+		return p[0], p[1], p[2]
+	case n < 232:
+		i := n - 16
+		return cubeLevels[i/36], cubeLevels[(i/6)%6], cubeLevels[i%6]
+	default:
+		v := 8 + (n-232)*10
+		return v, v, v
+	}
+}
+
+func rgbToIndexed(r, g, b uint8) uint8 {
+	cube := func(v uint8) uint8 {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	ci := 16 + 36*cube(r) + 6*cube(g) + cube(b)
+
+	// Prefer the grayscale ramp when it is closer than the cube entry.
+	avg := (int(r) + int(g) + int(b)) / 3
+	gi := uint8(232)
+	if avg > 238 {
+		gi = 255
+	} else if avg > 8 {
+		gi = 232 + uint8((avg-8)/10)
+	}
+	cr, cg, cb := indexedToRGB(ci)
+	gr, gg, gb := indexedToRGB(gi)
+	if colorDist(r, g, b, gr, gg, gb) < colorDist(r, g, b, cr, cg, cb) {
+		return gi
+	}
+	return ci
+}
+
+func rgbToBasic(r, g, b uint8) uint8 {
+	best, bestDist := uint8(0), -1
+	for i, p := range basicPalette {
+		if d := colorDist(r, g, b, p[0], p[1], p[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = uint8(i), d
+		}
+	}
+	return best
+}
+
+func colorDist(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+type styleAttr uint8
+
+const (
+	attrBold styleAttr = 1 << iota
+	attrDim
+	attrItalic
+	attrUnderline
+)
+
+// Style is an immutable set of text attributes. Builder methods return a
+// modified copy, so styles can be shared and extended freely:
+//
+//	warn := toolkit.Style{}.Foreground(toolkit.Yellow).Bold()
+//	fmt.Fprintln(rt.Stream().Err, warn.Render(rt.Terminal(), "warning"))
+type Style struct {
+	fg    Color
+	bg    Color
+	attrs styleAttr
+}
+
+// Foreground returns a copy of s with the text color set to c.
+func (s Style) Foreground(c Color) Style {
+	s.fg = c
+	return s
+}
+
+// Background returns a copy of s with the background color set to c.
+func (s Style) Background(c Color) Style {
+	s.bg = c
+	return s
+}
+
+// Bold returns a copy of s with bold text.
+func (s Style) Bold() Style {
+	s.attrs |= attrBold
+	return s
+}
+
+// Dim returns a copy of s with faint text.
+func (s Style) Dim() Style {
+	s.attrs |= attrDim
+	return s
+}
+
+// Italic returns a copy of s with italic text.
+func (s Style) Italic() Style {
+	s.attrs |= attrItalic
+	return s
+}
+
+// Underline returns a copy of s with underlined text.
+func (s Style) Underline() Style {
+	s.attrs |= attrUnderline
+	return s
+}
+
+// Render wraps text in the escape sequences for s. When t does not allow
+// color, or s sets nothing, text is returned unchanged.
+func (s Style) Render(t Terminal, text string) string {
+	if t.Color == ColorNone || text == "" {
+		return text
+	}
+	var params []string
+	for _, a := range []struct {
+		attr styleAttr
+		code string
+	}{
+		{attrBold, "1"},
+		{attrDim, "2"},
+		{attrItalic, "3"},
+		{attrUnderline, "4"},
+	} {
+		if s.attrs&a.attr != 0 {
+			params = append(params, a.code)
+		}
+	}
+	if p := s.fg.sgr(t.Color, 30); p != "" {
+		params = append(params, p)
+	}
+	if p := s.bg.sgr(t.Color, 40); p != "" {
+		params = append(params, p)
+	}
+	if len(params) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m" + text + "\x1b[0m"
+}
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// StripANSI removes ANSI CSI escape sequences from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
@@ -0,0 +1,50 @@
+package toolkit_test
+
+import (
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle_Render(t *testing.T) {
+	t.Parallel()
+
+	basic := toolkit.Terminal{Color: toolkit.ColorBasic}
+	c256 := toolkit.Terminal{Color: toolkit.Color256}
+	truecolor := toolkit.Terminal{Color: toolkit.ColorTrueColor}
+	orange := toolkit.RGBColor(255, 135, 0)
+
+	cases := []struct {
+		name  string
+		style toolkit.Style
+		term  toolkit.Terminal
+		want  string
+	}{
+		{name: "NoColorIsPlain", style: toolkit.Style{}.Foreground(toolkit.Red).Bold(), term: toolkit.Terminal{}, want: "hi"},
+		{name: "EmptyStyleIsPlain", style: toolkit.Style{}, term: truecolor, want: "hi"},
+		{name: "BoldRed", style: toolkit.Style{}.Foreground(toolkit.Red).Bold(), term: basic, want: "\x1b[1;31mhi\x1b[0m"},
+		{name: "BrightBackground", style: toolkit.Style{}.Background(toolkit.Blue.Bright()), term: basic, want: "\x1b[104mhi\x1b[0m"},
+		{name: "Indexed", style: toolkit.Style{}.Foreground(toolkit.IndexedColor(208)), term: c256, want: "\x1b[38;5;208mhi\x1b[0m"},
+		{name: "RGB", style: toolkit.Style{}.Foreground(orange), term: truecolor, want: "\x1b[38;2;255;135;0mhi\x1b[0m"},
+		{name: "RGBTo256", style: toolkit.Style{}.Foreground(orange), term: c256, want: "\x1b[38;5;208mhi\x1b[0m"},
+		{name: "RGBToBasic", style: toolkit.Style{}.Foreground(toolkit.RGBColor(250, 10, 10)), term: basic, want: "\x1b[91mhi\x1b[0m"},
+		{name: "GrayTo256", style: toolkit.Style{}.Foreground(toolkit.RGBColor(128, 128, 128)), term: c256, want: "\x1b[38;5;244mhi\x1b[0m"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.style.Render(tc.term, "hi"))
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	t.Parallel()
+
+	term := toolkit.Terminal{Color: toolkit.ColorTrueColor}
+	s := toolkit.Style{}.Foreground(toolkit.RGBColor(1, 2, 3)).Underline().Render(term, "text")
+	assert.Equal(t, "a text b", toolkit.StripANSI("a "+s+" b"))
+	assert.Equal(t, "plain", toolkit.StripANSI("plain"))
+}
@@ -0,0 +1,148 @@
+package toolkit
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorLevel describes how many colors a terminal can display.
+type ColorLevel int
+
+const (
+	// ColorNone disables all ANSI styling.
+	ColorNone ColorLevel = iota
+	// ColorBasic supports the 16 standard ANSI colors.
+	ColorBasic
+	// Color256 supports the xterm 256-color palette.
+	Color256
+	// ColorTrueColor supports 24-bit RGB colors.
+	ColorTrueColor
+)
+
+// String returns a short name for the level.
+func (l ColorLevel) String() string {
+	switch l {
+	case ColorNone:
+		return "none"
+	case ColorBasic:
+		return "basic"
+	case Color256:
+		return "256"
+	case ColorTrueColor:
+		return "truecolor"
+	default:
+		return "ColorLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// Terminal describes the output capabilities of a runtime stream.
+//
+// Width and Height are zero when the size cannot be determined.
+type Terminal struct {
+	// IsTTY mirrors Stream.IsTTY.
+	IsTTY bool
+	// Color is the color level styling may use.
+	Color ColorLevel
+	// Width is the number of columns.
+	Width int
+	// Height is the number of rows.
+	Height int
+	// Unicode reports whether the locale supports UTF-8 output.
+	Unicode bool
+}
+
+// Terminal resolves the capabilities of the runtime stream from the runtime
+// env. See [DetectTerminal] for the rules.
+func (rt *Runtime) Terminal() Terminal {
+	if rt == nil {
+		return DetectTerminal(nil, nil)
+	}
+	return DetectTerminal(rt.env, rt.stream)
+}
+
+// DetectTerminal resolves terminal capabilities for s using env.
+//
+// Color follows the common conventions, in order:
+//   - NO_COLOR set to any non-empty value disables color.
+//   - CLICOLOR_FORCE set to a value other than "0" enables color even when
+//     output is not a terminal.
+//   - Otherwise color requires a TTY, and CLICOLOR=0 or TERM=dumb disable it.
+//
+// The level is truecolor when COLORTERM is "truecolor" or "24bit", 256 when
+// TERM contains "256color", and basic otherwise.
+//
+// Size comes from term.GetSize when s.Out is a terminal file, falling back to
+// the COLUMNS and LINES variables. Unicode is detected from LC_ALL, LC_CTYPE
+// or LANG naming a UTF-8 codeset, or from a Windows Terminal session.
+//
+// Stream.Width and Stream.Height, when set, replace the detected size, and
+// Stream.ForceColor replaces the detected color level.
+//
+// A nil env uses the OS environment and a nil s uses [DefaultStream].
+func DetectTerminal(env Env, s *Stream) Terminal {
+	if env == nil {
+		env = &OsEnv{}
+	}
+	s = OrDefaultStream(s)
+
+	t := Terminal{IsTTY: s.IsTTY}
+	t.Color = detectColor(env, s.IsTTY)
+	if s.ForceColor {
+		t.Color = s.Color
+	}
+	t.Width, t.Height = detectSize(env, s)
+	t.Unicode = detectUnicode(env)
+	return t
+}
+
+func detectColor(env Env, isTTY bool) ColorLevel {
+	if env.Get("NO_COLOR") != "" {
+		return ColorNone
+	}
+	force := env.Get("CLICOLOR_FORCE")
+	forced := force != "" && force != "0"
+	if !forced {
+		if !isTTY || env.Get("CLICOLOR") == "0" || env.Get("TERM") == "dumb" {
+			return ColorNone
+		}
+	}
+
+	switch strings.ToLower(env.Get("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorTrueColor
+	}
+	if strings.Contains(env.Get("TERM"), "256color") {
+		return Color256
+	}
+	return ColorBasic
+}
+
+func detectSize(env Env, s *Stream) (int, int) {
+	if s.Width > 0 || s.Height > 0 {
+		return max(s.Width, 0), max(s.Height, 0)
+	}
+	if f, ok := s.Out.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if w, h, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+			return w, h
+		}
+	}
+	w, _ := strconv.Atoi(env.Get("COLUMNS"))
+	h, _ := strconv.Atoi(env.Get("LINES"))
+	return max(w, 0), max(h, 0)
+}
+
+func detectUnicode(env Env) bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		v := env.Get(key)
+		if v == "" {
+			continue
+		}
+		v = strings.ToLower(v)
+		return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+	}
+	return runtime.GOOS == "windows" && env.Get("WT_SESSION") != ""
+}
@@ -0,0 +1,80 @@
+package toolkit_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jlrickert/cli-toolkit/toolkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTerminal_Color(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		env   map[string]string
+		isTTY bool
+		want  toolkit.ColorLevel
+	}{
+		{name: "NotTTY", env: map[string]string{"TERM": "xterm"}, want: toolkit.ColorNone},
+		{name: "Basic", env: map[string]string{"TERM": "xterm"}, isTTY: true, want: toolkit.ColorBasic},
+		{name: "256", env: map[string]string{"TERM": "xterm-256color"}, isTTY: true, want: toolkit.Color256},
+		{name: "TrueColor", env: map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, isTTY: true, want: toolkit.ColorTrueColor},
+		{name: "Dumb", env: map[string]string{"TERM": "dumb"}, isTTY: true, want: toolkit.ColorNone},
+		{name: "NoColor", env: map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, isTTY: true, want: toolkit.ColorNone},
+		{name: "CLIColorOff", env: map[string]string{"TERM": "xterm", "CLICOLOR": "0"}, isTTY: true, want: toolkit.ColorNone},
+		{name: "ForceWithoutTTY", env: map[string]string{"TERM": "xterm-256color", "CLICOLOR_FORCE": "1"}, want: toolkit.Color256},
+		{name: "ForceZeroIgnored", env: map[string]string{"TERM": "xterm", "CLICOLOR_FORCE": "0"}, want: toolkit.ColorNone},
+		{name: "NoColorBeatsForce", env: map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, isTTY: true, want: toolkit.ColorNone},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			env := toolkit.NewTestEnv(t.TempDir(), "", "")
+			for k, v := range tc.env {
+				assert.NoError(t, env.Set(k, v))
+			}
+			s := &toolkit.Stream{Out: &bytes.Buffer{}, IsTTY: tc.isTTY}
+			assert.Equal(t, tc.want, toolkit.DetectTerminal(env, s).Color)
+		})
+	}
+}
+
+func TestDetectTerminal_SizeAndUnicode(t *testing.T) {
+	t.Parallel()
+
+	env := toolkit.NewTestEnv(t.TempDir(), "", "")
+	s := &toolkit.Stream{Out: &bytes.Buffer{}, IsTTY: true}
+
+	term := toolkit.DetectTerminal(env, s)
+	assert.Zero(t, term.Width)
+	assert.Zero(t, term.Height)
+	assert.False(t, term.Unicode)
+
+	assert.NoError(t, env.Set("COLUMNS", "120"))
+	assert.NoError(t, env.Set("LINES", "40"))
+	assert.NoError(t, env.Set("LANG", "en_US.UTF-8"))
+	term = toolkit.DetectTerminal(env, s)
+	assert.Equal(t, 120, term.Width)
+	assert.Equal(t, 40, term.Height)
+	assert.True(t, term.Unicode)
+
+	// LC_ALL takes precedence over LANG.
+	assert.NoError(t, env.Set("LC_ALL", "C"))
+	assert.False(t, toolkit.DetectTerminal(env, s).Unicode)
+}
+
+func TestRuntime_Terminal(t *testing.T) {
+	t.Parallel()
+
+	rt, err := toolkit.NewTestRuntime(t.TempDir(), "", "",
+		toolkit.WithRuntimeStream(&toolkit.Stream{Out: &bytes.Buffer{}, IsTTY: true}))
+	assert.NoError(t, err)
+	assert.NoError(t, rt.Set("TERM", "xterm-256color"))
+
+	term := rt.Terminal()
+	assert.True(t, term.IsTTY)
+	assert.Equal(t, toolkit.Color256, term.Color)
+}
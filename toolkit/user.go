@@ -1,9 +1,13 @@
 package toolkit
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
-	"os/exec"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -121,6 +125,33 @@ func UserStatePath(env Env) (string, error) {
 
 var DefaultEditor = "nano"
 
+// EditorCommand returns the argv for the user's editor. It prefers $VISUAL,
+// then $EDITOR, then [DefaultEditor], and splits the value with [ShellSplit]
+// so quoted paths and arguments such as '"/opt/My Editor/bin/edit" -w' work.
+func EditorCommand(rt *Runtime) ([]string, error) {
+	return resolveCommand(rt, DefaultEditor, "VISUAL", "EDITOR")
+}
+
+// resolveCommand splits the first non-blank value among keys, falling back to
+// fallback when none is set.
+func resolveCommand(rt *Runtime, fallback string, keys ...string) ([]string, error) {
+	line := fallback
+	for _, key := range keys {
+		if v := rt.Get(key); strings.TrimSpace(v) != "" {
+			line = v
+			break
+		}
+	}
+	argv, err := ShellSplit(rt, line)
+	if err != nil {
+		return nil, fmt.Errorf("parse command %q: %w", line, err)
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("parse command %q: empty command", line)
+	}
+	return argv, nil
+}
+
 // Edit launches the user's editor to edit the provided file path.
 func Edit(ctx context.Context, rt *Runtime, path string) error {
 	if path == "" {
@@ -131,33 +162,127 @@ func Edit(ctx context.Context, rt *Runtime, path string) error {
 	if err != nil {
 		return fmt.Errorf("resolve edit path: %w", err)
 	}
-	editorPath := resolvedPath
-	if jail := strings.TrimSpace(rt.GetJail()); jail != "" {
-		trimmed := strings.TrimPrefix(resolvedPath, string(filepath.Separator))
-		editorPath = filepath.Join(jail, trimmed)
+	editorPath := rt.hostPath(resolvedPath)
+
+	argv, err := EditorCommand(rt)
+	if err != nil {
+		return err
+	}
+	name := argv[0]
+	args := append(argv[1:], editorPath)
+	editor := strings.Join(argv, " ")
+
+	if err := rt.Command(ctx, name, args...).Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// EditOption configures [EditBytes].
+type EditOption func(*editOptions)
+
+type editOptions struct {
+	commentPrefix string
+}
+
+// WithCommentPrefix strips lines starting with prefix, such as "#", from the
+// edited content before it is returned. Comments in initial can then carry
+// instructions for the user, as git does for commit messages.
+func WithCommentPrefix(prefix string) EditOption {
+	return func(o *editOptions) {
+		o.commentPrefix = prefix
+	}
+}
+
+// EditBytes writes initial to a temporary file, opens it with the user's
+// editor via [Edit], and returns the edited content. suffix is appended to the
+// temp file name (for example ".md") so editors can pick a syntax mode.
+//
+// changed reports whether the content differs from initial, compared with the
+// runtime Hasher after comment stripping. The temp file is removed before
+// EditBytes returns.
+func EditBytes(ctx context.Context, rt *Runtime, initial []byte, suffix string, opts ...EditOption) ([]byte, bool, error) {
+	if err := rt.Validate(); err != nil {
+		return nil, false, err
+	}
+	o := &editOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
 	}
 
-	editor := rt.Get("VISUAL")
-	if strings.TrimSpace(editor) == "" {
-		editor = rt.Get("EDITOR")
+	dir := rt.GetTempDir()
+	if err := rt.Mkdir(dir, 0o755, true); err != nil {
+		return nil, false, fmt.Errorf("create temp dir: %w", err)
 	}
-	if strings.TrimSpace(editor) == "" {
-		editor = DefaultEditor
+	path, err := createEditFile(rt, dir, suffix, initial)
+	if err != nil {
+		return nil, false, err
 	}
+	defer func() {
+		if err := rt.Remove(path, false); err != nil {
+			rt.Logger().Debug("remove edit temp file", "path", path, "error", err)
+		}
+	}()
 
-	parts := strings.Fields(editor)
-	name := parts[0]
-	args := append(parts[1:], editorPath)
+	if err := Edit(ctx, rt, path); err != nil {
+		return nil, false, err
+	}
+	edited, err := rt.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("read edited file: %w", err)
+	}
 
-	cmd := exec.CommandContext(ctx, name, args...)
-	stream := rt.Stream()
-	cmd.Stdin = stream.In
-	cmd.Stdout = stream.Out
-	cmd.Stderr = stream.Err
-	cmd.Env = rt.Environ()
+	before := stripComments(initial, o.commentPrefix)
+	after := stripComments(edited, o.commentPrefix)
+	h := OrDefaultHasher(rt.hasher)
+	return after, h.Hash(before) != h.Hash(after), nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("running editor %q: %w", editor, err)
+// createEditFile writes data to a new file with a random name in dir. The
+// file is created exclusively, so an existing file is never reused; a name
+// collision picks a new name.
+func createEditFile(rt *Runtime, dir, suffix string, data []byte) (string, error) {
+	const attempts = 10
+	for range attempts {
+		var token [8]byte
+		if _, err := rand.Read(token[:]); err != nil {
+			return "", fmt.Errorf("create temp name: %w", err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("edit-%x%s", token, suffix))
+		w, err := rt.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("create temp file: %w", err)
+		}
+		_, err = w.Write(data)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = rt.Remove(path, false)
+			return "", fmt.Errorf("write temp file: %w", err)
+		}
+		return path, nil
 	}
-	return nil
+	return "", fmt.Errorf("create temp file in %s: %w", dir, fs.ErrExist)
+}
+
+// stripComments removes lines that start with prefix. An empty prefix
+// returns data unchanged.
+func stripComments(data []byte, prefix string) []byte {
+	if prefix == "" {
+		return data
+	}
+	out := make([]byte, 0, len(data))
+	for line := range bytes.Lines(data) {
+		if bytes.HasPrefix(line, []byte(prefix)) {
+			continue
+		}
+		out = append(out, line...)
+	}
+	return out
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	tu "github.com/jlrickert/cli-toolkit/sandbox"
 	"github.com/jlrickert/cli-toolkit/toolkit"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -232,3 +233,75 @@ func TestEdit_UsesRuntimeAndResolvesSymlinkPath(t *testing.T) {
 	want := filepath.Join(jailDir, "home", "alice", "work", "keg")
 	require.Equal(t, filepath.Clean(want), filepath.Clean(got))
 }
+
+func TestEditBytes(t *testing.T) {
+	t.Parallel()
+
+	const initial = "# Enter a message.\nhello\n"
+	cases := []struct {
+		name        string
+		edit        func([]byte) ([]byte, error)
+		opts        []toolkit.EditOption
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "Changed",
+			edit:        func(b []byte) ([]byte, error) { return append(b, "world\n"...), nil },
+			want:        initial + "world\n",
+			wantChanged: true,
+		},
+		{
+			name: "Unchanged",
+			edit: func(b []byte) ([]byte, error) { return b, nil },
+			want: initial,
+		},
+		{
+			name:        "StripsComments",
+			edit:        func(b []byte) ([]byte, error) { return []byte("# note\nbye\n"), nil },
+			opts:        []toolkit.EditOption{toolkit.WithCommentPrefix("#")},
+			want:        "bye\n",
+			wantChanged: true,
+		},
+		{
+			name: "CommentOnlyEditIsUnchanged",
+			edit: func(b []byte) ([]byte, error) { return append([]byte("# another\n"), b...), nil },
+			opts: []toolkit.EditOption{toolkit.WithCommentPrefix("#")},
+			want: "hello\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sb := tu.NewSandbox(t, nil, tu.WithEditor(tc.edit))
+			rt := sb.Runtime()
+
+			got, changed, err := toolkit.EditBytes(sb.Context(), rt, []byte(initial), ".md", tc.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+			assert.Equal(t, tc.wantChanged, changed)
+
+			calls := sb.Commands().Calls()
+			require.Len(t, calls, 1)
+			edited := calls[0].Argv[len(calls[0].Argv)-1]
+			assert.True(t, strings.HasSuffix(edited, ".md"), "temp file %q", edited)
+
+			entries, err := rt.ReadDir(rt.GetTempDir())
+			require.NoError(t, err)
+			assert.Empty(t, entries, "temp file is removed")
+		})
+	}
+}
+
+func TestEditBytes_EditorFails(t *testing.T) {
+	t.Parallel()
+
+	sb := tu.NewSandbox(t, nil, tu.WithEnv("EDITOR", "vim"))
+	sb.Commands().On("vim", "...").Exit(2)
+
+	_, _, err := toolkit.EditBytes(sb.Context(), sb.Runtime(), []byte("x"), ".txt")
+	var exitErr *toolkit.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.Code)
+}